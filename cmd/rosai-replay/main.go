@@ -0,0 +1,85 @@
+// Command rosai-replay sends the requests recorded by a FileRecorder to a new
+// build of the skill, and reports how its responses differ from the
+// recorded ones:
+//
+//	rosai-replay -url http://localhost:10000/weather traffic.jsonl.1 traffic.jsonl
+//
+// Give the rotated files first, oldest first, to keep the order of the
+// turns. With -dialog the prompts with several variations may be answered
+// with any of them. The access tokens were redacted by the recorder, -token
+// sends a token in their place, e.g. the one of a test user. The command exits
+// with status 1 when a turn differs.
+//
+// The sessions of a skill served at a url can not be seen, the ones recorded
+// are compared by replaying in-process from a test of the skill:
+//
+//	report := replay.New(handler).Replay(records)
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/replay"
+)
+
+var (
+	url        = flag.String("url", "", "the url of the new build of the skill")
+	ignore     = flag.String("ignore", "", "comma separated fields not compared")
+	dialogPath = flag.String("dialog", "", "the dialog model of the skill")
+	token      = flag.String("token", "", "the access token sent in place of the redacted ones")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s -url URL RECORDING...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *url == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	records, err := replay.Load(flag.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	r := replay.NewRemote(*url)
+	if *dialogPath != "" {
+		dm, err := loadDialogModel(*dialogPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load %s error: %s\n", *dialogPath, err)
+			os.Exit(2)
+		}
+		r.WithModel(dm)
+	}
+	if *token != "" {
+		r.WithAccessToken(*token)
+	}
+	if *ignore != "" {
+		r.WithIgnore(strings.Split(*ignore, ",")...)
+	}
+	report := r.Replay(records)
+	report.Print(os.Stdout)
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func loadDialogModel(path string) (*model.DialogModel, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
@@ -0,0 +1,73 @@
+// Command rosai-sim talks to a skill from the terminal, without the ROSAI
+// platform in front of it.
+//
+//	rosai-sim -dialog conf/dialog.json
+//	rosai-sim -dialog conf/dialog.json -url http://localhost:10000/planmytrip
+//
+// Without -url the dialog model is simulated in-process by a skill which
+// only delegates its dialogs, to try a dialog.json before any code is
+// written. Type help at the prompt for the commands.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/simulator"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+var (
+	dialogPath = flag.String("dialog", "./conf/dialog.json", "the dialog model of the skill")
+	url        = flag.String("url", "", "the url of the skill, simulated in-process when empty")
+	deviceId   = flag.String("device", "", "the id of the device sending the requests")
+	verbose    = flag.Bool("v", false, "print the logs of the in-process skill")
+)
+
+func main() {
+	flag.Parse()
+	dm, err := loadDialogModel(*dialogPath)
+	if err != nil && *url == "" {
+		fmt.Fprintf(os.Stderr, "load %s error: %s\n", *dialogPath, err)
+		os.Exit(1)
+	}
+	var sim *simulator.Simulator
+	if *url != "" {
+		sim = simulator.NewRemote(*url).WithModel(dm)
+	} else {
+		if !*verbose {
+			log.SetOutput(ioutil.Discard)
+		}
+		sim = simulator.New(&sp.RequestHandler{
+			Speechlet:   &simulator.DialogSpeechlet{},
+			DialogModel: dm,
+		})
+	}
+	if *deviceId != "" {
+		sim.Requests().WithDeviceId(*deviceId)
+	}
+	fmt.Print(simulator.Usage)
+	if err := sim.Run(os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// loadDialogModel returns a nil model when the file can not be loaded, yes
+// and no are then unavailable against a url.
+func loadDialogModel(path string) (*model.DialogModel, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
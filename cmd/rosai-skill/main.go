@@ -0,0 +1,156 @@
+// Command rosai-skill creates skill projects and adds intents to them:
+//
+//	rosai-skill new [-dir DIR] [-port PORT] NAME
+//	rosai-skill add-intent [-dir DIR] INTENT [SLOT[:TYPE] ...]
+//	rosai-skill lint [-pkg DIR] [-json] DIALOG.JSON
+//	rosai-skill gen [-o FILE] [-pkg NAME] DIALOG.JSON
+//
+// new creates a ready to run skill in DIR, ./NAME by default, NAME made of
+// a-z, 0-9 and -: its speechlet, main.go, an empty dialog model, config,
+// tests, a Dockerfile and a Makefile. add-intent adds the intent to
+// conf/dialog.json, generates dialog_gen.go again and stubs the handler of
+// the intent in the speechlet. lint reports the problems of a dialog model,
+// the slot handlers checked against the methods of the Go package in -pkg,
+// and exits with status 1 when one of them is an error. gen writes the typed
+// intents, the constants and the intent router of a dialog model, e.g. with
+//
+//	//go:generate rosai-skill gen -o dialog_gen.go conf/dialog.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/scaffold"
+)
+
+const usage = `usage:
+  rosai-skill new [-dir DIR] [-port PORT] NAME
+  rosai-skill add-intent [-dir DIR] INTENT [SLOT[:TYPE] ...]
+  rosai-skill lint [-pkg DIR] [-json] DIALOG.JSON
+  rosai-skill gen [-o FILE] [-pkg NAME] DIALOG.JSON
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "new":
+		err = newSkill(args)
+	case "add-intent":
+		err = addIntent(args)
+	case "lint":
+		err = lint(args)
+	case "gen":
+		err = gen(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newSkill(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	dir := fs.String("dir", "", "the directory of the project, ./NAME when empty")
+	port := fs.Int("port", scaffold.DefaultPort, "the port the skill listens on")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	name := fs.Arg(0)
+	if *dir == "" {
+		*dir = name
+	}
+	if err := scaffold.New(*dir, name, *port); err != nil {
+		return err
+	}
+	fmt.Printf("created %s in %s, try: cd %s && make test run\n", name, *dir, *dir)
+	return nil
+}
+
+func addIntent(args []string) error {
+	fs := flag.NewFlagSet("add-intent", flag.ExitOnError)
+	dir := fs.String("dir", ".", "the directory of the project")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := scaffold.AddIntent(*dir, fs.Arg(0), fs.Args()[1:]...); err != nil {
+		return err
+	}
+	fmt.Printf("added %s to %s, reword its prompts there\n", fs.Arg(0), scaffold.DialogPath)
+	return nil
+}
+
+// lintReport is the output of lint with -json.
+type lintReport struct {
+	File     string             `json:"file"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+	Issues   []*model.LintIssue `json:"issues"`
+}
+
+func lint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	pkg := fs.String("pkg", "", "the directory of the Go package of the slot handlers")
+	asJSON := fs.Bool("json", false, "print the issues as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	issues, err := scaffold.Lint(fs.Arg(0), *pkg)
+	if err != nil {
+		return err
+	}
+	report := &lintReport{File: fs.Arg(0), Issues: issues}
+	if report.Issues == nil {
+		report.Issues = []*model.LintIssue{}
+	}
+	for _, v := range issues {
+		if v.Severity == model.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range issues {
+			fmt.Printf("%s: %s\n", report.File, v)
+		}
+	}
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func gen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	out := fs.String("o", "dialog_gen.go", "the file generated")
+	pkg := fs.String("pkg", "", "the package of the file, the one of its directory when empty")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	return scaffold.GenerateFile(fs.Arg(0), *out, *pkg)
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// The codes of the issues found by Lint.
+const (
+	LintDuplicateIntent     = "duplicate-intent"
+	LintDuplicateSlot       = "duplicate-slot"
+	LintDuplicatePrompt     = "duplicate-prompt"
+	LintMissingPrompt       = "missing-prompt"
+	LintMissingElicitation  = "missing-elicitation"
+	LintMissingConfirmation = "missing-confirmation"
+	LintMissingResult       = "missing-result"
+	LintUnusedPrompt        = "unused-prompt"
+	LintUnknownPlaceholder  = "unknown-placeholder"
+	LintBarePlaceholder     = "bare-placeholder"
+	LintInvalidVariation    = "invalid-variation"
+	LintUnknownHandler      = "unknown-handler"
+	LintUnknownCarryOver    = "unknown-carry-over"
+)
+
+// LintIssue is a problem of the dialog model, Path tells where it is, e.g.
+// intents[PlanMyTrip].slots[toCity].
+type LintIssue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+func (li *LintIssue) String() string {
+	return fmt.Sprintf("%s: %s %s: %s", li.Path, li.Severity, li.Code, li.Message)
+}
+
+// placeholderRegexp matches the {$name} resolved with the slot values.
+var placeholderRegexp = regexp.MustCompile(`\{\$([^{}]*)\}`)
+
+// barePlaceholderRegexp matches the {name} missing their $, which are said
+// as they are.
+var barePlaceholderRegexp = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// Lint checks the dialog model in more details than Verify. The slot
+// handlers are checked against handlers, the names of the methods of the
+// SlotHandler, unless it is nil.
+func (dm *DialogModel) Lint(handlers []string) []*LintIssue {
+	l := &linter{dm: dm, prompts: make(map[string]*Prompt), used: make(map[string][]*Intent)}
+	for i, v := range dm.Prompts {
+		if v == nil {
+			continue
+		}
+		if _, ok := l.prompts[v.ID]; ok {
+			l.errorf(LintDuplicatePrompt, fmt.Sprintf("prompts[%d]", i),
+				"prompt id %s already used", v.ID)
+			continue
+		}
+		l.prompts[v.ID] = v
+	}
+	intents := make(map[string]bool)
+	for _, intent := range dm.Dialog.Intents {
+		path := fmt.Sprintf("intents[%s]", intent.Name)
+		if intents[intent.Name] {
+			l.errorf(LintDuplicateIntent, path, "intent %s declared twice", intent.Name)
+		}
+		intents[intent.Name] = true
+		l.checkPrompt(intent, path+".prompts.confirmation", intent.Prompts.Confirmation,
+			intent.NeedConfirm(), LintMissingConfirmation)
+		l.checkPrompt(intent, path+".prompts.result", intent.Prompts.Result,
+			intent.NeedResult(), LintMissingResult)
+		slots := make(map[string]bool)
+		for _, slot := range intent.Slots {
+			slotPath := fmt.Sprintf("%s.slots[%s]", path, slot.Name)
+			if slots[slot.Name] {
+				l.errorf(LintDuplicateSlot, slotPath, "slot %s declared twice in %s",
+					slot.Name, intent.Name)
+			}
+			slots[slot.Name] = true
+			l.checkPrompt(intent, slotPath+".prompts.elicitation", slot.Prompts.Elicitation,
+				slot.NeedElicit(), LintMissingElicitation)
+			l.checkPrompt(intent, slotPath+".prompts.confirmation", slot.Prompts.Confirmation,
+				slot.NeedConfirm(), LintMissingConfirmation)
+			if handlers != nil && slot.Handler != "" && !contains(handlers, slot.Handler) {
+				l.errorf(LintUnknownHandler, slotPath+".handler",
+					"handler %s is not a method of the slot handler", slot.Handler)
+			}
+		}
+	}
+	for i, co := range dm.Dialog.CarryOver {
+		path := fmt.Sprintf("carryOver[%d]", i)
+		for _, v := range co.Intents {
+			if dm.GetSlot(v, co.Slot) == nil {
+				l.errorf(LintUnknownCarryOver, path, "intent %s has no slot %s", v, co.Slot)
+			}
+		}
+	}
+	ids := make([]string, 0, len(l.prompts))
+	for k := range l.prompts {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		path := fmt.Sprintf("prompts[%s]", id)
+		if len(l.used[id]) == 0 && id != ErrorPromptId {
+			l.warnf(LintUnusedPrompt, path, "prompt %s is used by no intent", id)
+		}
+		l.checkVariations(path+".variations", l.prompts[id].Variations, l.used[id])
+		l.checkVariations(path+".reprompts", l.prompts[id].Reprompts, l.used[id])
+	}
+	return l.issues
+}
+
+type linter struct {
+	dm      *DialogModel
+	prompts map[string]*Prompt
+	// used are the intents using each prompt
+	used   map[string][]*Intent
+	issues []*LintIssue
+}
+
+func (l *linter) errorf(code, path, format string, args ...interface{}) {
+	l.issues = append(l.issues, &LintIssue{Severity: SeverityError, Code: code, Path: path,
+		Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) warnf(code, path, format string, args ...interface{}) {
+	l.issues = append(l.issues, &LintIssue{Severity: SeverityWarning, Code: code, Path: path,
+		Message: fmt.Sprintf(format, args...)})
+}
+
+// checkPrompt checks the prompt id, which the intent needs when required.
+func (l *linter) checkPrompt(intent *Intent, path, id string, required bool, code string) {
+	if id == "" {
+		if required {
+			l.errorf(code, path, "required but no prompt id given")
+		}
+		return
+	}
+	if _, ok := l.prompts[id]; !ok {
+		if required {
+			l.errorf(code, path, "required but prompt %s not found", id)
+		} else {
+			l.warnf(LintMissingPrompt, path, "prompt %s not found", id)
+		}
+		return
+	}
+	for _, v := range l.used[id] {
+		if v == intent {
+			return
+		}
+	}
+	l.used[id] = append(l.used[id], intent)
+}
+
+// checkVariations checks the values of the variations against their type,
+// and their placeholders against the slots of the intents using them.
+func (l *linter) checkVariations(path string, variations []*Variation, intents []*Intent) {
+	for i, v := range variations {
+		vpath := fmt.Sprintf("%s[%d]", path, i)
+		if v == nil || len(v.Value) == 0 {
+			l.errorf(LintInvalidVariation, vpath, "no value")
+			continue
+		}
+		for j, raw := range v.Value {
+			rpath := fmt.Sprintf("%s.value[%d]", vpath, j)
+			switch v.Type {
+			case "PlainText", "Audio":
+				var s string
+				if err := json.Unmarshal(raw, &s); err != nil {
+					l.errorf(LintInvalidVariation, rpath, "%s value is not a string: %s",
+						v.Type, raw)
+					continue
+				}
+				if v.Type == "PlainText" {
+					l.checkPlaceholders(rpath, s, intents)
+				}
+			case "Display.Customized":
+				var obj struct {
+					Card json.RawMessage `json:"card"`
+				}
+				if err := json.Unmarshal(raw, &obj); err != nil || len(obj.Card) == 0 {
+					l.errorf(LintInvalidVariation, rpath, "%s value is not an object with "+
+						"a card: %s", v.Type, raw)
+				}
+			default:
+				l.warnf(LintInvalidVariation, rpath, "variation type %q is ignored", v.Type)
+			}
+		}
+	}
+}
+
+func (l *linter) checkPlaceholders(path, text string, intents []*Intent) {
+	for _, m := range placeholderRegexp.FindAllStringSubmatch(text, -1) {
+		for _, intent := range intents {
+			if intent.GetSlot(m[1]) == nil {
+				l.errorf(LintUnknownPlaceholder, path, "{$%s} is not a slot of %s", m[1],
+					intent.Name)
+			}
+		}
+	}
+	for _, m := range barePlaceholderRegexp.FindAllStringSubmatch(text, -1) {
+		l.warnf(LintBarePlaceholder, path, "%s is said as it is, write {$%s}", m[0], m[1])
+		for _, intent := range intents {
+			if intent.GetSlot(m[1]) == nil {
+				l.warnf(LintUnknownPlaceholder, path, "%s is not a slot of %s", m[0],
+					intent.Name)
+			}
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, v := range names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const lintDialog = `{
+  "dialog": {
+    "intents": [
+      {
+        "name": "PlanMyTrip",
+        "confirmationRequired": true,
+        "prompts": {"result": "Result.PlanMyTrip"},
+        "slots": [
+          {"name": "toCity", "type": "CITY", "elicitationRequired": true,
+           "prompts": {"elicitation": "Elicit.toCity"}, "handler": "CheckCity"},
+          {"name": "toCity", "type": "CITY"},
+          {"name": "date", "type": "DATE", "elicitationRequired": true,
+           "handler": "CheckDate"}
+        ]
+      },
+      {"name": "PlanMyTrip", "slots": []}
+    ],
+    "carryOver": [{"slot": "fromCity", "intents": ["PlanMyTrip"]}]
+  },
+  "prompts": [
+    {"id": "Elicit.toCity", "variations": [
+      {"type": "PlainText", "value": ["Where to, {$fromCity}?"]},
+      {"type": "Display.Customized", "value": ["no card"]}]},
+    {"id": "Result.PlanMyTrip", "variations": [{"type": "PlainText", "value": ["Trip to {$toCity} on {date} from {fromCity}"]}]},
+    {"id": "Unused", "variations": [{"type": "Audio", "value": [42]}, {"type": "Video", "value": ["x"]}]},
+    {"id": "Unused", "variations": []},
+    {"id": "Skill.Error", "variations": [{"type": "PlainText", "value": ["Sorry"]}]}
+  ]
+}`
+
+func TestLint(t *testing.T) {
+	dm := new(DialogModel)
+	if err := json.Unmarshal([]byte(lintDialog), dm); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range dm.Lint([]string{"CheckCity"}) {
+		got = append(got, v.Code+" "+v.Path)
+	}
+	sort.Strings(got)
+	want := []string{
+		"bare-placeholder prompts[Result.PlanMyTrip].variations[0].value[0]",
+		"bare-placeholder prompts[Result.PlanMyTrip].variations[0].value[0]",
+		"duplicate-intent intents[PlanMyTrip]",
+		"duplicate-prompt prompts[3]",
+		"duplicate-slot intents[PlanMyTrip].slots[toCity]",
+		"invalid-variation prompts[Elicit.toCity].variations[1].value[0]",
+		"invalid-variation prompts[Unused].variations[0].value[0]",
+		"invalid-variation prompts[Unused].variations[1].value[0]",
+		"missing-confirmation intents[PlanMyTrip].prompts.confirmation",
+		"missing-elicitation intents[PlanMyTrip].slots[date].prompts.elicitation",
+		"unknown-carry-over carryOver[0]",
+		"unknown-handler intents[PlanMyTrip].slots[date].handler",
+		"unknown-placeholder prompts[Elicit.toCity].variations[0].value[0]",
+		"unknown-placeholder prompts[Result.PlanMyTrip].variations[0].value[0]",
+		"unused-prompt prompts[Unused]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("want issues:\n%s\ngot:\n%s", strings.Join(want, "\n"),
+			strings.Join(got, "\n"))
+	}
+}
+
+func TestLintValidModel(t *testing.T) {
+	data, err := ioutil.ReadFile("../../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := new(DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		t.Fatal(err)
+	}
+	// its confirmations say their placeholders without $
+	for _, v := range dm.Lint(nil) {
+		if v.Code != LintBarePlaceholder || !strings.HasPrefix(v.Path, "prompts[Confirm.") {
+			t.Fatalf("want no issue but bare placeholders, got: %v", v)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package model
 
 import "encoding/json"
 
+// ErrorPromptId is the prompt of the skill said when it fails to answer,
+// it belongs to no intent.
+const ErrorPromptId = "Skill.Error"
+
 type DialogModel struct {
 	Dialog  Dialog    `json:"dialog"`
 	Prompts []*Prompt `json:"prompts"`
@@ -97,6 +101,20 @@ func (dm *DialogModel) GetSlot(intentName, slotName string) *Slot {
 	return intent.GetSlot(slotName)
 }
 
+// GetCarryOver returns the rule sharing the slot with the intent, or nil if the
+// slot is not shared.
+func (dm *DialogModel) GetCarryOver(intentName, slotName string) *CarryOver {
+	if dm == nil {
+		return nil
+	}
+	for _, v := range dm.Dialog.CarryOver {
+		if v.Covers(intentName, slotName) {
+			return v
+		}
+	}
+	return nil
+}
+
 func (dm *DialogModel) Verify() bool {
 	for _, intent := range dm.Dialog.Intents {
 		if intent.NeedConfirm() && dm.GetIntentConfirmation(intent.Name) == nil {
@@ -118,7 +136,8 @@ func (dm *DialogModel) Verify() bool {
 }
 
 type Dialog struct {
-	Intents []*Intent `json:"intents"`
+	Intents   []*Intent    `json:"intents"`
+	CarryOver []*CarryOver `json:"carryOver,omitempty"`
 }
 
 func NewDialog(intents ...*Intent) Dialog {
@@ -127,6 +146,58 @@ func NewDialog(intents ...*Intent) Dialog {
 	return d
 }
 
+// CarryOver declares a slot shared by several intents: once the slot is
+// filled for one of them, it is inherited by the others during Turns intent
+// requests or LifespanInMs milliseconds, whichever ends first. A zero value
+// leaves the corresponding limit unset.
+type CarryOver struct {
+	Slot         string   `json:"slot"`
+	Intents      []string `json:"intents"`
+	Turns        int      `json:"turns,omitempty"`
+	LifespanInMs int64    `json:"lifespanInMs,omitempty"`
+}
+
+func NewCarryOver(slot string, intents ...string) *CarryOver {
+	return &CarryOver{Slot: slot, Intents: intents}
+}
+
+func (co *CarryOver) WithTurns(n int) *CarryOver {
+	co.Turns = n
+	return co
+}
+
+func (co *CarryOver) WithLifespanInMs(ms int64) *CarryOver {
+	co.LifespanInMs = ms
+	return co
+}
+
+func (co *CarryOver) Covers(intentName, slotName string) bool {
+	if co == nil || co.Slot != slotName {
+		return false
+	}
+	for _, v := range co.Intents {
+		if v == intentName {
+			return true
+		}
+	}
+	return false
+}
+
+// Alive reports whether a value filled turns intent requests and ageInMs
+// milliseconds ago can still be inherited.
+func (co *CarryOver) Alive(turns int, ageInMs int64) bool {
+	if co == nil {
+		return false
+	}
+	if co.Turns > 0 && turns > co.Turns {
+		return false
+	}
+	if co.LifespanInMs > 0 && ageInMs > co.LifespanInMs {
+		return false
+	}
+	return true
+}
+
 type Intent struct {
 	Name                 string    `json:"name"`
 	ConfirmationRequired bool      `json:"confirmationRequired"`
@@ -166,6 +237,20 @@ type Slot struct {
 	Prompts              PromptIds `json:"prompts"`
 	Handler              string    `json:"handler"`
 	ConcealRequired      bool      `json:"concealRequired"`
+	// ShareLifespan limits how long the slot shared to the context lives,
+	// nil shares it as long as the context lives.
+	ShareLifespan *Lifespan `json:"shareLifespan,omitempty"`
+}
+
+// Lifespan is counted in requests and/or milliseconds, a zero value leaves
+// the corresponding limit unset.
+type Lifespan struct {
+	Turns        int   `json:"turns,omitempty"`
+	LifespanInMs int64 `json:"lifespanInMs,omitempty"`
+}
+
+func NewLifespan(turns int, ms int64) *Lifespan {
+	return &Lifespan{Turns: turns, LifespanInMs: ms}
 }
 
 func NewSlot(name, typ string, c, e bool) *Slot {
@@ -195,6 +280,15 @@ func NewPromptIds(c, e string) PromptIds {
 type Prompt struct {
 	ID         string       `json:"id"`
 	Variations []*Variation `json:"variations"`
+	// Reprompts are said when the user stays silent after the prompt.
+	Reprompts []*Variation `json:"reprompts,omitempty"`
+}
+
+func (p *Prompt) GetID() string {
+	if p == nil {
+		return ""
+	}
+	return p.ID
 }
 
 /*func NewPrompt(id string, v []*Variation) *Prompt {
@@ -213,9 +307,16 @@ type Prompt struct {
 	return p.Variations[i].Value
 }*/
 
+// HasReprompts reports whether the prompt has its own reprompt variations.
+func (p *Prompt) HasReprompts() bool {
+	return p != nil && len(p.Reprompts) > 0
+}
+
 type Variation struct {
 	Type  string            `json:"type"`
 	Value []json.RawMessage `json:"value"`
+	// Emotion is the code of the emotion shown with the variation.
+	Emotion string `json:"emotion,omitempty"`
 }
 
 /*func NewVariation(t, v string) *Variation {
@@ -107,37 +107,41 @@ func (wt *Weather) OnIntent(re *sp.RequestEnvelope) (*sp.Response, *sp.Context,
 	}
 }
 
+// oneDayQuery and daysQuery are filled from the slots of the intent, or from
+// the context when the user did not say them again.
+type oneDayQuery struct {
+	City  string `slot:"city" ctx:"city"`
+	Date  string `slot:"date" ctx:"date"`
+	Focus string `slot:"focus" ctx:"focus" required:"true"`
+}
+
+type daysQuery struct {
+	City     string `slot:"city" ctx:"city"`
+	Duration string `slot:"duration" ctx:"duration"`
+	Focus    string `slot:"focus" ctx:"focus" required:"true"`
+}
+
 func handleSearchOneDayIntent(intent *slu.Intent, inCtx *sp.Context) (
 	resp *sp.Response, ctx *sp.Context, err error) {
 	defer func() {
 		ctx = inCtx
 	}()
-	var city, date, focus string
-	if city = intent.GetSlot(SlotCity).GetStringValue(); city == "" {
-		if city = inCtx.GetStringValue(SlotCity); city == "" {
-			glog.Infof("get city[%s] from context", city)
-			if city = getCityFromSysInfo(inCtx); city == "" {
-				return sp.NewAskResponse("你要查询哪个城市的天气"), nil, nil
-			} else {
-				glog.Infof("get city[%s] from context system info", city)
-				//inCtx.SetStringValue(SlotCity, city)
-			}
-		}
+	var q oneDayQuery
+	if err := slu.Bind(intent, inCtx, &q); err != nil {
+		glog.Warningf("SearchOneDay bind slots error: %s", err)
+		return nil, nil, err
 	}
-	if date = intent.GetSlot(SlotDate).GetStringValue(); date == "" {
-		if date = inCtx.GetStringValue(SlotDate); date == "" {
-			date = time.Now().Format("2006-01-02")
-			//return sp.NewAskResponse("你要查询哪一天的天气"), nil, nil
+	if q.City == "" {
+		if q.City = getCityFromSysInfo(inCtx); q.City == "" {
+			return sp.NewAskResponse("你要查询哪个城市的天气"), nil, nil
 		}
+		glog.Infof("get city[%s] from context system info", q.City)
 	}
-	if focus = intent.GetSlot(SlotFocus).GetStringValue(); focus == "" {
-		if focus = inCtx.GetStringValue(SlotFocus); focus == "" {
-			glog.Warning("focus is null")
-			return nil, nil, util.NewErr("focus for one day is null")
-		}
+	if q.Date == "" {
+		q.Date = time.Now().Format("2006-01-02")
 	}
-	glog.Infof("SearchOneDay slots city: %s, date: %s, focus: %s", city, date, focus)
-	return getFinalOneDayResponse(city, date, focus)
+	glog.Infof("SearchOneDay slots city: %s, date: %s, focus: %s", q.City, q.Date, q.Focus)
+	return getFinalOneDayResponse(q.City, q.Date, q.Focus)
 }
 
 func handleSearchDaysIntent(intent *slu.Intent, inCtx *sp.Context) (
@@ -145,31 +149,23 @@ func handleSearchDaysIntent(intent *slu.Intent, inCtx *sp.Context) (
 	defer func() {
 		ctx = inCtx
 	}()
-	var city, duration, focus string
-	if city = intent.GetSlot(SlotCity).GetStringValue(); city == "" {
-		if city = inCtx.GetStringValue(SlotCity); city == "" {
-			glog.Infof("get city[%s] from context", city)
-			if city = getCityFromSysInfo(inCtx); city == "" {
-				return sp.NewAskResponse("你要查询哪个城市的天气"), nil, nil
-			} else {
-				glog.Infof("get city[%s] from context system info", city)
-				//inCtx.SetStringValue(SlotCity, city)
-			}
-		}
+	var q daysQuery
+	if err := slu.Bind(intent, inCtx, &q); err != nil {
+		glog.Warningf("SearchDays bind slots error: %s", err)
+		return nil, nil, err
 	}
-	if duration = intent.GetSlot(SlotDuration).GetStringValue(); duration == "" {
-		if duration = inCtx.GetStringValue(SlotDuration); duration == "" {
-			return sp.NewAskResponse("你要查询哪段时间的天气"), nil, nil
+	if q.City == "" {
+		if q.City = getCityFromSysInfo(inCtx); q.City == "" {
+			return sp.NewAskResponse("你要查询哪个城市的天气"), nil, nil
 		}
+		glog.Infof("get city[%s] from context system info", q.City)
 	}
-	if focus = intent.GetSlot(SlotFocus).GetStringValue(); focus == "" {
-		if focus = inCtx.GetStringValue(SlotFocus); focus == "" {
-			glog.Warning("focus is null")
-			return nil, nil, util.NewErr("focus for days is null")
-		}
+	if q.Duration == "" {
+		return sp.NewAskResponse("你要查询哪段时间的天气"), nil, nil
 	}
-	glog.Infof("SearchDays slots city: %s, duration: %s, focus: %s", city, duration, focus)
-	return getFinalDaysResponse(city, duration, focus)
+	glog.Infof("SearchDays slots city: %s, duration: %s, focus: %s", q.City, q.Duration,
+		q.Focus)
+	return getFinalDaysResponse(q.City, q.Duration, q.Focus)
 }
 
 func getFinalOneDayResponse(city, date, focus string) (
@@ -0,0 +1,156 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+// Driver sends the requests of a builder to a skill turn after turn, and
+// carries the context of the responses over to the next requests as the
+// platform does. The skill is either a RequestHandler called in-process,
+// whose sessions are then kept in memory, or a RequestHandler served at a
+// URL.
+type Driver struct {
+	call     func(reqBytes []byte) ([]byte, error)
+	requests *Builder
+
+	// in-process only
+	store    *sp.MemorySessionStore
+	recorder *recorder
+}
+
+// recorder keeps the response of the turn, which tells whether the session
+// ended.
+type recorder struct {
+	resp *sp.Response
+}
+
+func (r *recorder) PostProcess(reqEn *sp.RequestEnvelope, resp *sp.Response) {
+	r.resp = resp
+}
+
+// NewDriver drives a copy of the handler whose sessions are kept in memory,
+// without its traffic recorder.
+func NewDriver(handler *sp.RequestHandler) *Driver {
+	d := &Driver{
+		requests: New(),
+		store:    sp.NewMemorySessionStore(),
+		recorder: &recorder{},
+	}
+	h := *handler
+	h.SessionStore = d.store
+	h.Recorder = nil
+	h.PostProcessors = append(append([]sp.ResponsePostProcessor(nil),
+		handler.PostProcessors...), d.recorder)
+	d.call = h.HandleCall
+	return d
+}
+
+// NewRemoteDriver drives the skill served at the url, e.g.
+// http://localhost:10000/planmytrip.
+func NewRemoteDriver(url string) *Driver {
+	return &Driver{
+		call: func(reqBytes []byte) ([]byte, error) {
+			return post(url, reqBytes)
+		},
+		requests: New(),
+	}
+}
+
+func post(url string, reqBytes []byte) ([]byte, error) {
+	resp, err := http.Post(url, "application/json", bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("%s: %s", resp.Status, body))
+	}
+	return body, nil
+}
+
+// WithRequests makes the driver send the requests of the builder.
+func (d *Driver) WithRequests(b *Builder) *Driver {
+	d.requests = b
+	return d
+}
+
+func (d *Driver) Requests() *Builder {
+	return d.requests
+}
+
+// InProcess reports whether the handler is called in-process, its sessions
+// can then be seen.
+func (d *Driver) InProcess() bool {
+	return d.store != nil
+}
+
+// Call sends the request as it is, e.g. a recorded one, and returns the
+// response as it is.
+func (d *Driver) Call(reqBytes []byte) ([]byte, error) {
+	if d.recorder != nil {
+		d.recorder.resp = nil
+	}
+	return d.call(reqBytes)
+}
+
+// Send sends the request as the next turn, the context returned is sent back
+// with the next requests of the builder.
+func (d *Driver) Send(reqEn *sp.RequestEnvelope) (*sp.ResponseEnvelopeRaw, error) {
+	reqBytes, err := json.Marshal(reqEn)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("marshal request %s: %s",
+			reqEn.Request.GetType(), err))
+	}
+	respBytes, err := d.Call(reqBytes)
+	if err != nil {
+		return nil, err
+	}
+	respEn := new(sp.ResponseEnvelopeRaw)
+	if err := json.Unmarshal(respBytes, respEn); err != nil {
+		return nil, errors.New(fmt.Sprintf("unmarshal response %s: %s", respBytes, err))
+	}
+	d.requests.Continue(respEn.Context)
+	return respEn, nil
+}
+
+// Ended reports whether the last turn ended the session, always false for a
+// remote skill.
+func (d *Driver) Ended() bool {
+	return d.recorder != nil && d.recorder.resp.ShouldEnded()
+}
+
+// Session returns the session of the user of the builder as saved after the
+// last turn.
+func (d *Driver) Session() (*sp.Session, error) {
+	return d.SessionOf(d.requests.Context())
+}
+
+// SessionOf returns the session of the user of the context.
+func (d *Driver) SessionOf(ctx *sp.Context) (*sp.Session, error) {
+	if d.store == nil {
+		return nil, errors.New("sessions of a remote skill can not be seen")
+	}
+	return d.store.Fetch(ctx.GetUserId(), ctx.GetAppId(), ctx.GetDeviceId(),
+		ctx.GetSkillId())
+}
+
+// DropSession drops the session of the user of the builder, as the platform
+// does once it ended.
+func (d *Driver) DropSession() {
+	if d.store == nil {
+		return
+	}
+	ctx := d.requests.Context()
+	d.store.Drop(ctx.GetUserId(), ctx.GetAppId(), ctx.GetDeviceId(), ctx.GetSkillId())
+}
@@ -0,0 +1,176 @@
+// Package fixtures builds realistic request envelopes for tests, simulators
+// and replay tools:
+//
+//	b := fixtures.New().WithDeviceId("rosai1.device.002")
+//	reqEn := b.IntentWithSlots("PlanMyTrip", map[string]string{"toCity": "Seattle"})
+//
+// The requests of a builder have ids numbered in sequence and share the
+// same user, device and skill. A Driver sends them to a skill turn after
+// turn.
+package fixtures
+
+import (
+	"fmt"
+	"time"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+const (
+	DefaultUserId   = "rosai1.ask.account.fixtures001"
+	DefaultAppId    = "rosai1.ask.developer.fixtures001"
+	DefaultDeviceId = "rosai1.device.fixtures001"
+	DefaultSkillId  = "rosai1.ask.skill.fixtures.v1.0"
+)
+
+type Builder struct {
+	ctx *sp.Context
+	seq int
+	now func() time.Time
+}
+
+// New returns a builder of requests from the default user, device and skill.
+func New() *Builder {
+	return &Builder{
+		ctx: sp.NewContext().WithSystem(sp.NewCtxSystem().
+			WithUser(sp.NewUser(DefaultUserId, DefaultAppId)).
+			WithDevice(sp.NewDevice(DefaultDeviceId)).
+			WithSkill(sp.NewSkill(DefaultSkillId))),
+		now: time.Now,
+	}
+}
+
+func (b *Builder) WithUser(userId, appId string) *Builder {
+	b.ctx.System.WithUser(sp.NewUser(userId, appId))
+	return b
+}
+
+func (b *Builder) WithAccessToken(token string) *Builder {
+	b.ctx.System.User.WithAccessToken(token)
+	return b
+}
+
+func (b *Builder) WithDeviceId(deviceId string) *Builder {
+	b.ctx.System.WithDevice(sp.NewDevice(deviceId))
+	return b
+}
+
+// WithDevice makes the requests come from the device, e.g. to declare its
+// supported interfaces.
+func (b *Builder) WithDevice(dev *sp.Device) *Builder {
+	b.ctx.System.WithDevice(dev)
+	return b
+}
+
+func (b *Builder) WithSkill(skillId string) *Builder {
+	b.ctx.System.WithSkill(sp.NewSkill(skillId))
+	return b
+}
+
+func (b *Builder) WithSysParameter(k string, v *slu.Value) *Builder {
+	b.ctx.WithSysParameter(k, v)
+	return b
+}
+
+func (b *Builder) WithParameter(k string, v *slu.Value) *Builder {
+	b.ctx.WithParameter(k, v)
+	return b
+}
+
+// WithClock makes the timestamps of the requests from now.
+func (b *Builder) WithClock(now func() time.Time) *Builder {
+	b.now = now
+	return b
+}
+
+// Continue carries the context returned with a response over to the next
+// requests, as the platform does.
+func (b *Builder) Continue(respCtx *sp.Context) *Builder {
+	if respCtx == nil {
+		return b
+	}
+	b.ctx.Context = respCtx.Context
+	b.ctx.LifespanInMs = respCtx.LifespanInMs
+	b.ctx.CtxParams = respCtx.CtxParams
+	b.ctx.Lifespans = respCtx.Lifespans
+	return b
+}
+
+// Context returns a copy of the context sent with the requests.
+func (b *Builder) Context() *sp.Context {
+	ctx := *b.ctx
+	ctx.CtxParams = copyParams(b.ctx.CtxParams)
+	if b.ctx.Lifespans != nil {
+		ctx.Lifespans = make(map[string]*sp.ParamLifespan, len(b.ctx.Lifespans))
+		for k, v := range b.ctx.Lifespans {
+			ls := *v
+			ctx.Lifespans[k] = &ls
+		}
+	}
+	if b.ctx.System != nil {
+		sys := *b.ctx.System
+		sys.CtxParams = copyParams(b.ctx.System.CtxParams)
+		ctx.System = &sys
+	}
+	return &ctx
+}
+
+func copyParams(params sp.CtxParams) sp.CtxParams {
+	if params == nil {
+		return nil
+	}
+	ret := make(sp.CtxParams, len(params))
+	for k, v := range params {
+		ret[k] = v
+	}
+	return ret
+}
+
+// NextRequestId returns the id of the next request of the sequence.
+func (b *Builder) NextRequestId() string {
+	b.seq++
+	return fmt.Sprintf("rosai1.ask.request.%04d", b.seq)
+}
+
+func (b *Builder) Timestamp() string {
+	return b.now().Format(time.RFC3339)
+}
+
+// Envelope wraps the request with the context of the builder.
+func (b *Builder) Envelope(req sp.Request) *sp.RequestEnvelope {
+	return sp.NewRequestEnvelope().WithContext(b.Context()).WithRequest(req)
+}
+
+func (b *Builder) Launch() *sp.RequestEnvelope {
+	return b.Envelope(sp.NewLaunchRequest(b.NextRequestId(), b.Timestamp()))
+}
+
+func (b *Builder) Intent(intent *slu.Intent) *sp.RequestEnvelope {
+	return b.Envelope(sp.NewIntentRequest(b.NextRequestId(), b.Timestamp(), intent))
+}
+
+// IntentWithSlots makes an IntentRequest of the intent with the string
+// values of the slots.
+func (b *Builder) IntentWithSlots(name string, slots map[string]string) *sp.RequestEnvelope {
+	return b.Intent(NewIntent(name, slots))
+}
+
+func (b *Builder) Intents(intents ...*slu.Intent) *sp.RequestEnvelope {
+	return b.Envelope(sp.NewIntentsRequest(b.NextRequestId(), b.Timestamp(), intents))
+}
+
+func (b *Builder) SessionEnded(reason sp.Reason) *sp.RequestEnvelope {
+	return b.Envelope(sp.NewSessionEndedRequest(b.NextRequestId(), b.Timestamp(),
+		reason, nil))
+}
+
+// NewIntent makes the intent with the string values of the slots, said as
+// they are by the user.
+func NewIntent(name string, slots map[string]string) *slu.Intent {
+	intent := slu.NewIntent(name)
+	for k, v := range slots {
+		intent.SetSlot(slu.NewSlot(k).WithValue(slu.NewStringValue(v).WithOrigin(v)))
+	}
+	return intent
+}
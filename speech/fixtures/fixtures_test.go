@@ -0,0 +1,166 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+func TestBuilder(t *testing.T) {
+	now := time.Date(2018, 4, 6, 15, 30, 2, 0, time.FixedZone("CST", 8*3600))
+	b := New().WithDeviceId("rosai1.device.002").WithAccessToken("token").
+		WithClock(func() time.Time { return now }).
+		WithSysParameter("city", slu.NewStringValue("Beijing"))
+
+	envelopes := []*sp.RequestEnvelope{
+		b.Launch(),
+		b.IntentWithSlots("PlanMyTrip", map[string]string{"toCity": "Seattle"}),
+		b.Intents(slu.NewIntent("PlanMyTrip"), slu.NewIntent("ROSAI.HelpIntent")),
+		b.SessionEnded(sp.USER_INITIATED),
+	}
+	types := []sp.RequestType{sp.LaunchRequestType, sp.IntentRequestType,
+		sp.IntentsRequestType, sp.SessionEndedRequestType}
+	ids := []string{"rosai1.ask.request.0001", "rosai1.ask.request.0002",
+		"rosai1.ask.request.0003", "rosai1.ask.request.0004"}
+	for i, v := range envelopes {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqEn, err := sp.ParseRequestEnvelope(raw)
+		if err != nil {
+			t.Fatalf("%s: %s", raw, err)
+		}
+		req := reqEn.Request
+		if req.GetType() != types[i] || req.GetRequestId() != ids[i] {
+			t.Fatalf("request %d: got %s %s", i, req.GetType(), req.GetRequestId())
+		}
+		if got := req.GetTimestamp(); got != "2018-04-06T15:30:02+08:00" {
+			t.Fatalf("request %d: got timestamp %s", i, got)
+		}
+		ctx := reqEn.Context
+		if ctx.GetUserId() != DefaultUserId || ctx.GetAppId() != DefaultAppId ||
+			ctx.GetSkillId() != DefaultSkillId || ctx.GetDeviceId() != "rosai1.device.002" ||
+			ctx.GetUserAccessToken() != "token" {
+			t.Fatalf("request %d: got context %s", i, raw)
+		}
+		if got := ctx.GetSysStringValue("city"); got != "Beijing" {
+			t.Fatalf("request %d: got sys parameter city %q", i, got)
+		}
+	}
+	intent := envelopes[1].Request.(*sp.IntentRequest).GetIntent()
+	if got := intent.GetSlot("toCity").GetStringValue(); got != "Seattle" {
+		t.Fatalf("got slot toCity %q", got)
+	}
+	if got := intent.GetSlot("toCity").GetValue().GetOrigin(); got != "Seattle" {
+		t.Fatalf("got origin of slot toCity %v", got)
+	}
+}
+
+func TestBuilderContinue(t *testing.T) {
+	b := New()
+	first := b.Launch()
+	b.Continue(sp.NewContext().WithStringValue("toCity", "Seattle"))
+	if got := b.Launch().Context.GetParameters().GetStringValue("toCity"); got != "Seattle" {
+		t.Fatalf("got parameter toCity %q", got)
+	}
+	// the contexts sent are copies
+	if first.Context.GetParameters().GetStringValue("toCity") != "" {
+		t.Fatal("the context of a sent request changed")
+	}
+	b.WithParameter("toCity", slu.NewStringValue("Paris"))
+	if got := b.Context().GetParameters().GetStringValue("toCity"); got != "Paris" {
+		t.Fatalf("got parameter toCity %q", got)
+	}
+}
+
+func loadTripModel(t *testing.T) *model.DialogModel {
+	data, err := ioutil.ReadFile("../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		t.Fatal(err)
+	}
+	return dm
+}
+
+type goodbyeSpeechlet struct{}
+
+func (gs *goodbyeSpeechlet) OnSessionStarted(re *sp.RequestEnvelope) error {
+	return nil
+}
+
+func (gs *goodbyeSpeechlet) OnLaunch(re *sp.RequestEnvelope) (*sp.Response, error) {
+	return sp.NewTellResponse("goodbye"), nil
+}
+
+func (gs *goodbyeSpeechlet) OnIntent(re *sp.RequestEnvelope) (*sp.Response, *sp.Context, error) {
+	return sp.NewAskResponse("again?"), sp.NewContext().WithStringValue("toCity", "Seattle"), nil
+}
+
+func (gs *goodbyeSpeechlet) OnSessionEnded(re *sp.RequestEnvelope) error {
+	return nil
+}
+
+func TestDriver(t *testing.T) {
+	d := NewDriver(&sp.RequestHandler{Speechlet: &goodbyeSpeechlet{},
+		DialogModel: loadTripModel(t)})
+	if !d.InProcess() {
+		t.Fatal("want in-process driver")
+	}
+	if _, err := d.Send(d.Requests().IntentWithSlots("PlanMyTrip", nil)); err != nil {
+		t.Fatal(err)
+	}
+	// the context returned is sent with the next requests
+	if got := d.Requests().Context().GetParameters().GetStringValue("toCity"); got != "Seattle" {
+		t.Fatalf("got parameter toCity %q", got)
+	}
+	if d.Ended() {
+		t.Fatal("want session going on")
+	}
+	if _, err := d.Send(d.Requests().Launch()); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Ended() {
+		t.Fatal("want session ended")
+	}
+	if _, err := d.Session(); err != nil {
+		t.Fatal(err)
+	}
+	d.DropSession()
+
+	if _, err := NewRemoteDriver("http://localhost:0").Session(); err == nil {
+		t.Fatal("want no session of a remote skill")
+	}
+}
+
+func TestNormalizer(t *testing.T) {
+	dm := loadTripModel(t)
+	dm.Prompts[0].Variations[0].Value = []json.RawMessage{
+		json.RawMessage(`"From where to {$toCity}?"`), json.RawMessage(`"Leaving from?"`)}
+	n := NewNormalizer(dm, "lifespans")
+	respEn := &sp.ResponseEnvelopeRaw{
+		Context: sp.NewContext().WithStringValue("fromCity", "Leaving from?"),
+		Results: json.RawMessage(`[{"hint":"From where to Paris?","outputSpeech":` +
+			`{"items":[{"source":"Leaving from?","type":"PlainText"}]}}]`),
+	}
+	respEn.Context.SetParameterLifespan("fromCity", 1, 1000)
+	data, _ := json.Marshal(n.Normalize(respEn))
+	// the slot values equal to a prompt are kept
+	want := `{"context":{"lifespans":"\u003cignored\u003e","parameters":{"fromCity":` +
+		`{"norm":"Leaving from?","normType":"String","orgin":null}}},` +
+		`"results":[{"hint":"\u003cone of Elicit.Slot.1159719883683.896729637610\u003e",` +
+		`"outputSpeech":{"items":[{"source":` +
+		`"\u003cone of Elicit.Slot.1159719883683.896729637610\u003e","type":"PlainText"}]}}],` +
+		`"status":null,"version":""}`
+	if string(data) != want {
+		t.Fatalf("want %s, got: %s", want, data)
+	}
+}
@@ -0,0 +1,147 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+// VolatileFields change from one run to the other, their values are ignored
+// by the normalizers.
+var VolatileFields = []string{"requestId", "timestamp", "expiresAtMs"}
+
+const (
+	IgnoredValue = "<ignored>"
+	// the values of a prompt with several values are chosen at random
+	randomValueFormat = "<one of %s>"
+)
+
+// Normalizer replaces the volatile values of the responses, so that they can
+// be compared from one run to the other: the ignored fields, and the hints
+// and speeches chosen at random among the variations of a prompt.
+type Normalizer struct {
+	ignore map[string]bool
+	// randomValues match the values of the prompts chosen at random, with
+	// their slot placeholders resolved
+	randomValues []*randomValue
+}
+
+type randomValue struct {
+	pattern  *regexp.Regexp
+	promptId string
+}
+
+// slotPlaceholderRegexp matches the {$name} of the prompts resolved with the
+// slot values.
+var slotPlaceholderRegexp = regexp.MustCompile(`\{\$[^{}]*\}`)
+
+// NewNormalizer ignores the fields in addition to VolatileFields, dm may be
+// nil when the prompts are unknown.
+func NewNormalizer(dm *model.DialogModel, ignore ...string) *Normalizer {
+	n := &Normalizer{ignore: make(map[string]bool)}
+	for _, v := range append(append([]string(nil), VolatileFields...), ignore...) {
+		n.ignore[v] = true
+	}
+	if dm == nil {
+		return n
+	}
+	for _, p := range dm.Prompts {
+		variations := append(append([]*model.Variation(nil), p.Variations...), p.Reprompts...)
+		for _, v := range variations {
+			if v == nil || len(v.Value) < 2 {
+				continue
+			}
+			for _, raw := range v.Value {
+				var s string
+				if json.Unmarshal(raw, &s) == nil {
+					n.randomValues = append(n.randomValues,
+						&randomValue{pattern: valuePattern(s), promptId: p.GetID()})
+				}
+			}
+		}
+	}
+	return n
+}
+
+// valuePattern matches the value of a prompt with any slot value in place of
+// its placeholders.
+func valuePattern(value string) *regexp.Regexp {
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	last := 0
+	for _, m := range slotPlaceholderRegexp.FindAllStringIndex(value, -1) {
+		buf.WriteString(regexp.QuoteMeta(value[last:m[0]]))
+		buf.WriteString("(?s:.*?)")
+		last = m[1]
+	}
+	buf.WriteString(regexp.QuoteMeta(value[last:]))
+	buf.WriteString("$")
+	return regexp.MustCompile(buf.String())
+}
+
+// Normalize returns v as decoded from its JSON, normalized.
+func (n *Normalizer) Normalize(v interface{}) interface{} {
+	data, _ := json.Marshal(v)
+	ret, _ := n.NormalizeJSON(data)
+	return ret
+}
+
+// NormalizeJSON decodes the JSON data and normalizes it.
+func (n *Normalizer) NormalizeJSON(data []byte) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return n.walk(v, false), nil
+}
+
+// walk replaces the ignored fields, and the random values of the hints and of
+// the sources of the speeches.
+func (n *Normalizer) walk(v interface{}, speech bool) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			switch {
+			case n.ignore[k]:
+				vv[k] = IgnoredValue
+			case k == "hint" || speech && k == "source":
+				vv[k] = n.randomValue(e)
+			default:
+				vv[k] = n.walk(e, speech || k == "outputSpeech" || k == "fallbackSpeech")
+			}
+		}
+	case []interface{}:
+		for i, e := range vv {
+			vv[i] = n.walk(e, speech)
+		}
+	}
+	return v
+}
+
+func (n *Normalizer) randomValue(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	for _, rv := range n.randomValues {
+		if rv.pattern.MatchString(s) {
+			return fmt.Sprintf(randomValueFormat, rv.promptId)
+		}
+	}
+	return v
+}
+
+// Render returns the value as compact JSON, (none) for nil.
+func Render(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(raw)
+}
@@ -0,0 +1,79 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+var (
+	interfaceTypesMu sync.RWMutex
+	interfaceTypes   = map[string]func() Interface{
+		DisplayType:     func() Interface { return &Display{} },
+		AudioPlayerType: func() Interface { return &AudioPlayer{} },
+		SSMLType:        func() Interface { return &SSML{} },
+		H5Type:          func() Interface { return &H5{} },
+		EmotionType:     func() Interface { return &Emotion{} },
+	}
+)
+
+// RegisterInterfaceType makes UnmarshalInterfaces decode the interfaces of
+// type typ into the value returned by newInterface, which must be a pointer.
+func RegisterInterfaceType(typ string, newInterface func() Interface) {
+	interfaceTypesMu.Lock()
+	defer interfaceTypesMu.Unlock()
+	interfaceTypes[typ] = newInterface
+}
+
+// RawInterface keeps an interface of a type not registered, so that it
+// encodes back as it was received.
+type RawInterface struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+func (ri *RawInterface) GetType() string {
+	return ri.Type
+}
+
+func (ri *RawInterface) MarshalJSON() ([]byte, error) {
+	return ri.Raw, nil
+}
+
+// UnmarshalInterface decodes the interface declared as typ into the type
+// registered for it.
+func UnmarshalInterface(typ string, raw []byte) (Interface, error) {
+	interfaceTypesMu.RLock()
+	newInterface, ok := interfaceTypes[typ]
+	interfaceTypesMu.RUnlock()
+	if !ok {
+		return &RawInterface{Type: typ, Raw: append(json.RawMessage(nil), raw...)}, nil
+	}
+	i := newInterface()
+	if err := json.Unmarshal(raw, i); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// UnmarshalInterfaces decodes the supported interfaces of a device, an
+// object keyed by their types:
+//
+//	{"Display": {"cardTypes": ["Text", "List"]}, "SSML": {}}
+func UnmarshalInterfaces(raw []byte) (map[string]Interface, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	ret := make(map[string]Interface, len(m))
+	for k, v := range m {
+		i, err := UnmarshalInterface(k, v)
+		if err != nil {
+			return nil, err
+		}
+		ret[k] = i
+	}
+	return ret, nil
+}
@@ -0,0 +1,72 @@
+package interfaces
+
+const (
+	DisplayType     = "Display"
+	AudioPlayerType = "AudioPlayer"
+	SSMLType        = "SSML"
+	H5Type          = "H5"
+	EmotionType     = "Emotion"
+)
+
+// Display is declared by the devices with a screen. CardTypes lists the
+// cards they can show, all of them when empty.
+type Display struct {
+	Version   string   `json:"version,omitempty"`
+	CardTypes []string `json:"cardTypes,omitempty"`
+}
+
+func (d *Display) GetType() string {
+	return DisplayType
+}
+
+func (d *Display) SupportsCard(typ string) bool {
+	return len(d.CardTypes) == 0 || containsString(d.CardTypes, typ)
+}
+
+type AudioPlayer struct {
+	Version string `json:"version,omitempty"`
+}
+
+func (ap *AudioPlayer) GetType() string {
+	return AudioPlayerType
+}
+
+type SSML struct {
+	Version string `json:"version,omitempty"`
+}
+
+func (s *SSML) GetType() string {
+	return SSMLType
+}
+
+// H5 is declared by the devices rendering H5 pages. Templates lists the
+// H5 templates they have, all of them when empty.
+type H5 struct {
+	Version   string   `json:"version,omitempty"`
+	Templates []string `json:"templates,omitempty"`
+}
+
+func (h *H5) GetType() string {
+	return H5Type
+}
+
+func (h *H5) SupportsTemplate(id string) bool {
+	return len(h.Templates) == 0 || containsString(h.Templates, id)
+}
+
+type Emotion struct {
+	Version string `json:"version,omitempty"`
+}
+
+func (e *Emotion) GetType() string {
+	return EmotionType
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+// Package replay runs the requests recorded by a speechlet.FileRecorder
+// again, against a new build of the skill, and reports per turn how its
+// responses and sessions differ from the recorded ones:
+//
+//	records, err := replay.Load("traffic.jsonl.1", "traffic.jsonl")
+//	report := replay.New(handler).Replay(records)
+//	report.Print(os.Stdout)
+//
+// The recorder replaces the access tokens with speechlet.RedactedValue, the
+// requests are replayed with that value unless WithAccessToken gives a token
+// to send instead: a skill which calls a service with the token of the user
+// would otherwise fail where the recorded turn succeeded. The sessions
+// recorded are compared only when replaying in-process, see New.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/fixtures"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+type Replayer struct {
+	driver *fixtures.Driver
+	model  *model.DialogModel
+	// Ignore are the fields not compared, in addition to
+	// fixtures.VolatileFields and the redacted ones.
+	Ignore []string
+	// AccessToken replaces the redacted access tokens of the requests.
+	AccessToken string
+}
+
+// New replays against a copy of the handler whose sessions are kept in
+// memory, the sessions are then compared too.
+func New(handler *sp.RequestHandler) *Replayer {
+	return &Replayer{driver: fixtures.NewDriver(handler), model: handler.DialogModel}
+}
+
+// NewRemote replays against the skill served at the url, only the responses
+// are compared.
+func NewRemote(url string) *Replayer {
+	return &Replayer{driver: fixtures.NewRemoteDriver(url)}
+}
+
+// WithModel gives the dialog model of the skill, whose prompts with several
+// variations may be answered with any of them.
+func (r *Replayer) WithModel(dm *model.DialogModel) *Replayer {
+	r.model = dm
+	return r
+}
+
+func (r *Replayer) WithIgnore(fields ...string) *Replayer {
+	r.Ignore = fields
+	return r
+}
+
+// WithAccessToken sends the token in place of the access tokens the recorder
+// redacted, e.g. the one of a test user.
+func (r *Replayer) WithAccessToken(token string) *Replayer {
+	r.AccessToken = token
+	return r
+}
+
+// Load reads the records of the files in turn, give the rotated files first
+// to keep the order of the turns.
+func Load(paths ...string) ([]*sp.TrafficRecord, error) {
+	var records []*sp.TrafficRecord
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		recs, err := Read(f)
+		f.Close()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%s: %s", path, err))
+		}
+		records = append(records, recs...)
+	}
+	return records, nil
+}
+
+// Read reads records written as JSON lines.
+func Read(in io.Reader) ([]*sp.TrafficRecord, error) {
+	var records []*sp.TrafficRecord
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec := new(sp.TrafficRecord)
+		if err := json.Unmarshal(line, rec); err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %s", n, err))
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// TurnDiff is how a turn replayed differs from the record.
+type TurnDiff struct {
+	Turn      int
+	RequestId string
+	Diffs     []string
+}
+
+type Report struct {
+	Turns int
+	Diffs []*TurnDiff
+	// Sessions tells whether the sessions were compared too.
+	Sessions bool
+}
+
+// OK reports whether all the turns behaved as recorded.
+func (rep *Report) OK() bool {
+	return len(rep.Diffs) == 0
+}
+
+func (rep *Report) Print(w io.Writer) {
+	for _, v := range rep.Diffs {
+		fmt.Fprintf(w, "turn %d (%s):\n", v.Turn, v.RequestId)
+		for _, d := range v.Diffs {
+			fmt.Fprintf(w, "  %s\n", d)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d turns differ\n", len(rep.Diffs), rep.Turns)
+	if !rep.Sessions {
+		fmt.Fprintln(w, "sessions not compared, they are only seen replaying in-process")
+	}
+}
+
+// Replay sends the requests of the records in turn and compares the results,
+// the speeches and hints of the prompts with several variations match any
+// of them. A conversation recorded from its middle can not have its first
+// session rebuilt, its first turn is reported as such.
+func (r *Replayer) Replay(records []*sp.TrafficRecord) *Report {
+	norm := fixtures.NewNormalizer(r.model, r.ignored()...)
+	rep := &Report{Turns: len(records), Sessions: r.driver.InProcess()}
+	for i, rec := range records {
+		turn := &TurnDiff{Turn: i + 1, RequestId: rec.RequestId}
+		if r.driver.InProcess() && !r.sessionReproduced(rec) {
+			turn.Diffs = append(turn.Diffs, "session before differs from the recorded one")
+		}
+		reqBytes, err := r.request(rec)
+		if err != nil {
+			turn.Diffs = append(turn.Diffs, fmt.Sprintf("request: %s", err))
+			rep.Diffs = append(rep.Diffs, turn)
+			continue
+		}
+		respBytes, err := r.driver.Call(reqBytes)
+		switch {
+		case err != nil && rec.Error == "":
+			turn.Diffs = append(turn.Diffs, fmt.Sprintf("error: %s", err))
+		case err == nil && rec.Error != "":
+			turn.Diffs = append(turn.Diffs, fmt.Sprintf("no error, recorded: %s", rec.Error))
+		case err == nil:
+			turn.Diffs = append(turn.Diffs, diffJSON("response", rec.Response,
+				respBytes, norm)...)
+		}
+		if r.driver.InProcess() && len(rec.SessionAfter) > 0 {
+			turn.Diffs = append(turn.Diffs, diffJSON("session", rec.SessionAfter,
+				r.session(rec), norm)...)
+		}
+		if len(turn.Diffs) > 0 {
+			rep.Diffs = append(rep.Diffs, turn)
+		}
+	}
+	return rep
+}
+
+func (r *Replayer) ignored() []string {
+	return append(append([]string(nil), r.Ignore...), sp.DefaultRedactedFields...)
+}
+
+// request returns the request of the record, with AccessToken in place of
+// the redacted access tokens.
+func (r *Replayer) request(rec *sp.TrafficRecord) ([]byte, error) {
+	if r.AccessToken == "" {
+		return []byte(rec.Request), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(rec.Request))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	restoreToken(doc, r.AccessToken)
+	return json.Marshal(doc)
+}
+
+func restoreToken(v interface{}, token string) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			if k == "accessToken" && e == sp.RedactedValue {
+				vv[k] = token
+				continue
+			}
+			restoreToken(e, token)
+		}
+	case []interface{}:
+		for _, e := range vv {
+			restoreToken(e, token)
+		}
+	}
+}
+
+// sessionReproduced tells whether the session the request is replayed with
+// is the one it was recorded with.
+func (r *Replayer) sessionReproduced(rec *sp.TrafficRecord) bool {
+	if len(rec.SessionBefore) == 0 {
+		return true
+	}
+	return len(diffJSON("", rec.SessionBefore, r.session(rec),
+		fixtures.NewNormalizer(nil, sp.DefaultRedactedFields...))) == 0
+}
+
+func (r *Replayer) session(rec *sp.TrafficRecord) []byte {
+	reqEn, err := sp.ParseRequestEnvelope(rec.Request)
+	if err != nil || reqEn.Context == nil {
+		return nil
+	}
+	ss, err := r.driver.SessionOf(reqEn.Context)
+	if err != nil {
+		return nil
+	}
+	raw, _ := json.Marshal(ss)
+	return raw
+}
+
+// diffJSON lists the paths whose values differ once normalized, e.g.
+// response.results[0].hint: "a" -> "b".
+func diffJSON(name string, want, got []byte, norm *fixtures.Normalizer) []string {
+	w, err := norm.NormalizeJSON(want)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: recorded %s", name, err)}
+	}
+	g, err := norm.NormalizeJSON(got)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %s", name, err)}
+	}
+	var diffs []string
+	diffValue(name, w, g, &diffs)
+	return diffs
+}
+
+func diffValue(path string, want, got interface{}, diffs *[]string) {
+	wm, wok := want.(map[string]interface{})
+	gm, gok := got.(map[string]interface{})
+	if wok && gok {
+		keys := make([]string, 0, len(wm)+len(gm))
+		for k := range wm {
+			keys = append(keys, k)
+		}
+		for k := range gm {
+			if _, ok := wm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffValue(path+"."+k, wm[k], gm[k], diffs)
+		}
+		return
+	}
+	wa, wok := want.([]interface{})
+	ga, gok := got.([]interface{})
+	if wok && gok && len(wa) == len(ga) {
+		for i := range wa {
+			diffValue(fmt.Sprintf("%s[%d]", path, i), wa[i], ga[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(want, got) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %s -> %s", strings.TrimPrefix(path, "."),
+			fixtures.Render(want), fixtures.Render(got)))
+	}
+}
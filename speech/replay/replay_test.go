@@ -0,0 +1,168 @@
+package replay
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/fixtures"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/simulator"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+func newTripHandler(t *testing.T, welcome string) *sp.RequestHandler {
+	data, err := ioutil.ReadFile("../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		t.Fatal(err)
+	}
+	return &sp.RequestHandler{
+		Speechlet:   &simulator.DialogSpeechlet{Welcome: welcome},
+		DialogModel: dm,
+	}
+}
+
+func record(t *testing.T, handler *sp.RequestHandler) []*sp.TrafficRecord {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traffic.jsonl")
+	recorder := sp.NewFileRecorder(path)
+	handler.SessionStore = sp.NewMemorySessionStore()
+	handler.Recorder = recorder
+	b := fixtures.New().WithAccessToken("secret")
+	for _, v := range []*sp.RequestEnvelope{
+		b.Launch(),
+		b.IntentWithSlots("PlanMyTrip", map[string]string{"toCity": "Paris"}),
+		b.IntentWithSlots("PlanMyTrip", map[string]string{"travelDate": "2018-04-11"}),
+	} {
+		reqBytes, _ := json.Marshal(v)
+		if _, err := handler.HandleCall(reqBytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder.Close()
+	records, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestReplay(t *testing.T) {
+	records := record(t, newTripHandler(t, "Hello"))
+	if len(records) != 3 {
+		t.Fatalf("want 3 records, got: %d", len(records))
+	}
+	report := New(newTripHandler(t, "Hello")).
+		Replay(records)
+	if !report.OK() {
+		out := new(strings.Builder)
+		report.Print(out)
+		t.Fatalf("want no diff, got:\n%s", out)
+	}
+	report = New(newTripHandler(t, "Hi")).
+		Replay(records)
+	// only the launch changed
+	if len(report.Diffs) != 1 || report.Diffs[0].Turn != 1 {
+		t.Fatalf("want the first turn differing, got: %+v", report.Diffs)
+	}
+	want := `response.results[0].outputSpeech.items[0].source: "Hello" -> "Hi"`
+	if diffs := report.Diffs[0].Diffs; len(diffs) != 1 || diffs[0] != want {
+		t.Fatalf("want %s, got: %q", want, diffs)
+	}
+}
+
+// tokenSpeechlet welcomes with the access token of the user.
+type tokenSpeechlet struct {
+	simulator.DialogSpeechlet
+}
+
+func (ts *tokenSpeechlet) OnLaunch(re *sp.RequestEnvelope) (*sp.Response, error) {
+	return sp.NewAskResponse("token " + re.Context.GetUserAccessToken()), nil
+}
+
+func TestReplayAccessToken(t *testing.T) {
+	handler := newTripHandler(t, "")
+	handler.Speechlet = &tokenSpeechlet{}
+	records := record(t, handler)
+	if strings.Contains(string(records[0].Request), "secret") {
+		t.Fatalf("want the token redacted, got: %s", records[0].Request)
+	}
+	handler = newTripHandler(t, "")
+	handler.Speechlet = &tokenSpeechlet{}
+	report := New(handler).Replay(records)
+	want := `response.results[0].outputSpeech.items[0].source: "token secret" -> ` +
+		`"token [REDACTED]"`
+	if len(report.Diffs) != 1 || report.Diffs[0].Diffs[0] != want {
+		t.Fatalf("want %s, got: %+v", want, report.Diffs)
+	}
+	handler = newTripHandler(t, "")
+	handler.Speechlet = &tokenSpeechlet{}
+	if report := New(handler).WithAccessToken("secret").Replay(records); !report.OK() {
+		t.Fatalf("want no diff with the token, got: %+v", report.Diffs[0])
+	}
+}
+
+func TestReplaySessions(t *testing.T) {
+	records := record(t, newTripHandler(t, "Hello"))
+	if report := New(newTripHandler(t, "Hello")).Replay(records); !report.Sessions {
+		t.Fatal("want the sessions compared in-process")
+	}
+	handler := newTripHandler(t, "Hello")
+	handler.SessionStore = sp.NewMemorySessionStore()
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	report := NewRemote(srv.URL).Replay(records)
+	out := new(strings.Builder)
+	report.Print(out)
+	if report.Sessions || !strings.Contains(out.String(), "sessions not compared") {
+		t.Fatalf("want the sessions not compared remotely, got:\n%s", out)
+	}
+}
+
+func TestReplayFromMiddle(t *testing.T) {
+	records := record(t, newTripHandler(t, "Hello"))
+	report := New(newTripHandler(t, "Hello")).
+		Replay(records[2:])
+	if len(report.Diffs) != 1 || !strings.Contains(report.Diffs[0].Diffs[0], "session before") {
+		t.Fatalf("want session before reported, got: %+v", report.Diffs)
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	diffs := diffJSON("r", []byte(`{"a":1,"b":[1,2],"requestId":"x","c":{"d":"e"}}`),
+		[]byte(`{"a":2,"b":[1],"requestId":"y","f":true}`), fixtures.NewNormalizer(nil))
+	want := []string{"r.a: 1 -> 2", "r.b: [1,2] -> [1]", `r.c: {"d":"e"} -> (none)`,
+		"r.f: (none) -> true"}
+	if strings.Join(diffs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("want %q, got %q", want, diffs)
+	}
+}
+
+func TestDiffJSONRandomVariations(t *testing.T) {
+	dm := newTripHandler(t, "").DialogModel
+	dm.Prompts[0].Variations[0].Value = []json.RawMessage{
+		json.RawMessage(`"From where to {$toCity}?"`), json.RawMessage(`"Leaving from?"`)}
+	norm := fixtures.NewNormalizer(dm)
+	// either variation may be said, with any slot value
+	diffs := diffJSON("r", []byte(`{"hint":"Leaving from?"}`),
+		[]byte(`{"hint":"From where to Paris?"}`), norm)
+	if len(diffs) != 0 {
+		t.Fatalf("want no diff, got: %q", diffs)
+	}
+	diffs = diffJSON("r", []byte(`{"hint":"Leaving from?"}`), []byte(`{"hint":"Hello"}`), norm)
+	if len(diffs) != 1 {
+		t.Fatalf("want the hint differing, got: %q", diffs)
+	}
+}
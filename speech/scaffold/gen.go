@@ -0,0 +1,275 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+// SlotGoTypes are the Go types of the slot fields by slot type, one of int,
+// bool, float64, string and []string. The slots of the types missing are
+// read as strings, add the other types of the skill before Generate, e.g.
+// SlotGoTypes["ROSAI.NUMBER"] = "int".
+var SlotGoTypes = map[string]string{
+	DefaultSlotType:  "string",
+	"ROSAI.DATE":     "string",
+	"ROSAI.DURATION": "string",
+	"ROSAI.CITY":     "string",
+	"ROSAI.US_CITY":  "string",
+	"ROSAI.ZH_CITY":  "string",
+}
+
+// slotGetters are the slu.Slot calls reading the Go types, which convert the
+// values of another type, e.g. the string "3" read as an int.
+var slotGetters = map[string]string{
+	"int":      "IntOrDefault(0)",
+	"bool":     "BoolOrDefault(false)",
+	"float64":  "FloatOrDefault(0)",
+	"string":   `StringOrDefault("")`,
+	"[]string": "StrArrayOrDefault(nil)",
+}
+
+type genModel struct {
+	Source  string
+	Package string
+	Intents []*genIntent
+	Slots   []*genConst
+	Prompts []*genConst
+}
+
+type genIntent struct {
+	Name   string
+	Const  string
+	Type   string
+	Method string
+	Fields []*genField
+}
+
+type genField struct {
+	Name    string
+	Const   string
+	Type    string
+	Getter  string
+	Comment string
+}
+
+type genConst struct {
+	Name  string
+	Value string
+}
+
+// Generate writes the Go code of the dialog model read from source, in the
+// package: constants of the intent, slot and prompt names, a struct per
+// intent with a field per slot, and an IntentHandler interface with a method
+// per intent which RouteIntent calls. It is meant for go generate:
+//
+//	//go:generate rosai-skill gen -o dialog_gen.go conf/dialog.json
+//
+// RouteIntent then replaces the switch on the intent names in OnIntent.
+func Generate(dm *model.DialogModel, source, pkg string) ([]byte, error) {
+	gm := &genModel{Source: source, Package: pkg}
+	names := newGoNames()
+	slots := make(map[string]string)
+	for _, intent := range dm.Dialog.Intents {
+		gi := &genIntent{Name: intent.Name, Const: "Intent" + goName(intent.Name)}
+		var err error
+		if gi.Type, gi.Method, err = intentGoNames(intent.Name); err != nil {
+			return nil, err
+		}
+		for _, v := range []string{gi.Type, "New" + gi.Type, gi.Const} {
+			if err := names.add(v, intent.Name); err != nil {
+				return nil, err
+			}
+		}
+		fields := newGoNames()
+		fields.add("Intent", "")
+		for _, slot := range intent.Slots {
+			gf := &genField{Name: goName(slot.Name), Const: "Slot" + goName(slot.Name),
+				Type: SlotGoTypes[slot.Type]}
+			if gf.Type == "" {
+				gf.Type = "string"
+				gf.Comment = fmt.Sprintf("slot type %s read as a string", slot.Type)
+			}
+			gf.Getter = slotGetters[gf.Type]
+			if gf.Getter == "" {
+				return nil, errors.New(fmt.Sprintf("slot type %s has Go type %s which can "+
+					"not be read", slot.Type, gf.Type))
+			}
+			if err := fields.add(gf.Name, intent.Name+"."+slot.Name); err != nil {
+				return nil, err
+			}
+			if prev, ok := slots[gf.Const]; !ok {
+				if err := names.add(gf.Const, slot.Name); err != nil {
+					return nil, err
+				}
+				slots[gf.Const] = slot.Name
+				gm.Slots = append(gm.Slots, &genConst{Name: gf.Const, Value: slot.Name})
+			} else if prev != slot.Name {
+				return nil, errors.New(fmt.Sprintf("slots %s and %s are both named %s",
+					prev, slot.Name, gf.Const))
+			}
+			gi.Fields = append(gi.Fields, gf)
+		}
+		gm.Intents = append(gm.Intents, gi)
+	}
+	for _, v := range dm.Prompts {
+		gc := &genConst{Name: "Prompt" + goName(v.ID), Value: v.ID}
+		if err := names.add(gc.Name, v.ID); err != nil {
+			return nil, err
+		}
+		gm.Prompts = append(gm.Prompts, gc)
+	}
+	sort.Slice(gm.Slots, func(i, j int) bool { return gm.Slots[i].Name < gm.Slots[j].Name })
+	buf := new(bytes.Buffer)
+	if err := genTemplate.Execute(buf, gm); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("format generated code: %s", err))
+	}
+	return src, nil
+}
+
+// intentGoNames returns the type of the intent and the method of the
+// IntentHandler handling it, PlanMyTripIntent and OnPlanMyTrip for
+// PlanMyTrip.
+func intentGoNames(name string) (typ, method string, err error) {
+	typ = goName(name)
+	if typ == "" {
+		return "", "", errors.New(fmt.Sprintf("intent name %q has no letter", name))
+	}
+	method = "On" + typ
+	if !strings.HasSuffix(typ, "Intent") {
+		typ += "Intent"
+	}
+	return typ, method, nil
+}
+
+// goNames detects the names given twice.
+type goNames map[string]string
+
+func newGoNames() goNames {
+	return make(goNames)
+}
+
+func (gn goNames) add(name, from string) error {
+	if prev, ok := gn[name]; ok {
+		return errors.New(fmt.Sprintf("%s and %s are both named %s", prev, from, name))
+	}
+	gn[name] = from
+	return nil
+}
+
+var genTemplate = template.Must(template.New("gen").Parse(`// Code generated by rosai-skill gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"errors"
+	"fmt"
+{{if .Intents}}
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"{{end}}
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+{{if .Intents}}
+const (
+{{- range .Intents}}
+	{{.Const}} = {{printf "%q" .Name}}
+{{- end}}
+)
+{{end}}{{if .Slots}}
+const (
+{{- range .Slots}}
+	{{.Name}} = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}{{if .Prompts}}
+const (
+{{- range .Prompts}}
+	{{.Name}} = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}
+{{- range .Intents}}
+{{$intent := .}}
+// {{.Type}} is the {{.Name}} intent with the values of its slots.
+type {{.Type}} struct {
+	Intent *slu.Intent
+{{- range .Fields}}
+	{{.Name}} {{.Type}}{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+func New{{.Type}}(intent *slu.Intent) *{{.Type}} {
+	return &{{.Type}}{
+		Intent: intent,
+{{- range .Fields}}
+		{{.Name}}: intent.GetSlot({{.Const}}).{{.Getter}},
+{{- end}}
+	}
+}
+{{- end}}
+
+// IntentHandler handles the intents of the dialog model, see RouteIntent.
+type IntentHandler interface {
+{{- range .Intents}}
+	{{.Method}}(request *sp.IntentRequest, intent *{{.Type}}) (*sp.Response, *sp.Context, error)
+{{- end}}
+}
+
+// RouteIntent calls the method of the handler for the intent of the request.
+func RouteIntent(handler IntentHandler, request *sp.IntentRequest) (*sp.Response, *sp.Context, error) {
+	switch request.IntentName() {
+{{- range .Intents}}
+	case {{.Const}}:
+		return handler.{{.Method}}(request, New{{.Type}}(request.Intent))
+{{- end}}
+	}
+	return nil, nil, errors.New(fmt.Sprintf("intent %s not in the dialog model", request.IntentName()))
+}
+`))
+
+// GenerateFile generates the code of the dialog model at dialogPath into
+// outPath. The package is the one of the Go files next to outPath when pkg
+// is empty, main if there is none.
+func GenerateFile(dialogPath, outPath, pkg string) error {
+	dm, err := loadDialogModel(dialogPath)
+	if err != nil {
+		return err
+	}
+	if pkg == "" {
+		if pkg, err = packageName(filepath.Dir(outPath)); err != nil {
+			return err
+		}
+	}
+	src, err := Generate(dm, filepath.ToSlash(dialogPath), pkg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(outPath, src, 0644)
+}
+
+func packageName(dir string) (string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	for k := range pkgs {
+		return k, nil
+	}
+	return "main", nil
+}
@@ -0,0 +1,71 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+func TestGenerate(t *testing.T) {
+	dm, err := loadDialogModel("../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm.GetSlot("PlanMyActivity", "actions").Type = "ROSAI.LIST"
+	dm.GetSlot("PlanMyActivity", "toCity").Type = "ROSAI.CITY"
+	dm.GetSlot("PlanMyTrip", "travelDate").Type = "ROSAI.NUMBER"
+	SlotGoTypes["ROSAI.LIST"], SlotGoTypes["ROSAI.NUMBER"] = "[]string", "int"
+	defer delete(SlotGoTypes, "ROSAI.LIST")
+	defer delete(SlotGoTypes, "ROSAI.NUMBER")
+	src, err := Generate(dm, "conf/dialog.json", "trip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "dialog_gen.go", src, 0); err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+	for _, want := range []string{
+		"// Code generated by rosai-skill gen from conf/dialog.json. DO NOT EDIT.",
+		"package trip",
+		`IntentPlanMyTrip     = "PlanMyTrip"`,
+		`SlotToCity     = "toCity"`,
+		`PromptElicitSlot537103921542444738461149   = "Elicit.Slot.537103921542.444738461149"`,
+		"type PlanMyActivityIntent struct {\n\tIntent  *slu.Intent\n\tToCity  string\n\tActions []string\n}",
+		"TravelDate: intent.GetSlot(SlotTravelDate).IntOrDefault(0),",
+		`FromCity:   intent.GetSlot(SlotFromCity).StringOrDefault(""),`,
+		"FromCity   string // slot type AMAZON.US_CITY read as a string",
+		"OnPlanMyTrip(request *sp.IntentRequest, intent *PlanMyTripIntent) (*sp.Response, *sp.Context, error)",
+		"case IntentPlanMyTrip:\n\t\treturn handler.OnPlanMyTrip(request, NewPlanMyTripIntent(request.Intent))",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("want %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateEmptyModel(t *testing.T) {
+	src, err := Generate(&model.DialogModel{}, "dialog.json", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(src), "speech/slu") {
+		t.Fatalf("want slu not imported without intents, got:\n%s", src)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "dialog_gen.go", src, 0); err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	dm := new(model.DialogModel)
+	json.Unmarshal([]byte(`{"dialog": {"intents": [{"name": "Trip", "slots": [`+
+		`{"name": "to-city", "type": "CITY"}, {"name": "toCity", "type": "CITY"}]}]}}`), dm)
+	if _, err := Generate(dm, "dialog.json", "main"); err == nil ||
+		!strings.Contains(err.Error(), "ToCity") {
+		t.Fatalf("want ToCity collision, got: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package scaffold
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+// Lint lints the dialog model at path. The slot handlers are checked against
+// the exported methods declared in the Go package in pkgDir, unless it is
+// empty.
+func Lint(path, pkgDir string) ([]*model.LintIssue, error) {
+	dm, err := loadDialogModel(path)
+	if err != nil {
+		return nil, err
+	}
+	var handlers []string
+	if pkgDir != "" {
+		if handlers, err = packageMethods(pkgDir); err != nil {
+			return nil, err
+		}
+	}
+	return dm.Lint(handlers), nil
+}
+
+// packageMethods returns the names of the exported methods declared in the
+// package, which may be slot handlers.
+func packageMethods(dir string) ([]string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				fd, ok := d.(*ast.FuncDecl)
+				if ok && fd.Recv != nil && fd.Name.IsExported() {
+					names = append(names, fd.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
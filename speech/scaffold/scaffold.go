@@ -0,0 +1,304 @@
+// Package scaffold creates skill projects and adds intents to them, it is
+// the library of the rosai-skill command:
+//
+//	scaffold.New("./planmytrip", "planmytrip", 10000)
+//	scaffold.AddIntent("./planmytrip", "PlanMyTrip", "toCity:ROSAI.CITY", "travelDate:ROSAI.DATE")
+//
+// A new project is ready to run: its speechlet delegates the dialogs to
+// conf/dialog.json and AddIntent stubs the handler of each intent added,
+// the IntentHandler method of the intent generated in dialog_gen.go.
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+const (
+	DefaultPort = 10000
+	// Marker is where AddIntent adds the cases of the intents in the switch
+	// of the speechlet.
+	Marker = "// rosai-skill:intents"
+	// DefaultSlotType is the type of the slots added without one.
+	DefaultSlotType = "ROSAI.STRING"
+
+	DialogPath = "conf/dialog.json"
+	// GenPath is the code generated from the dialog model, see Generate.
+	GenPath = "dialog_gen.go"
+)
+
+// nameRegexp matches the names of the skills, which are the names of files
+// and of the app id too.
+var nameRegexp = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// Project is what the templates of a new skill are executed with.
+type Project struct {
+	Name    string
+	Type    string
+	AppId   string
+	Port    int
+	Welcome string
+	Marker  string
+}
+
+func newProject(name string, port int) (*Project, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, errors.New(fmt.Sprintf("skill name %q not made of a-z, 0-9 and -",
+			name))
+	}
+	typ := goName(name)
+	if typ == "" {
+		return nil, errors.New(fmt.Sprintf("skill name %q has no letter", name))
+	}
+	if port <= 1023 || port > 65535 {
+		return nil, errors.New(fmt.Sprintf("port %d not in 1024-65535", port))
+	}
+	return &Project{
+		Name:    name,
+		Type:    typ,
+		AppId:   fmt.Sprintf("rosai1.ask.skill.%s.v1.0", name),
+		Port:    port,
+		Welcome: fmt.Sprintf("Welcome to %s, what can I do for you?", name),
+		Marker:  Marker,
+	}, nil
+}
+
+// goName turns a name like plan-my-trip or ROSAI.HelpIntent into an
+// exported Go identifier, PlanMyTrip or ROSAIHelpIntent.
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+	return b.String()
+}
+
+// New creates the project of the skill in dir, which must not exist or be
+// empty. The name is made of a-z, 0-9 and -.
+func New(dir, name string, port int) error {
+	p, err := newProject(name, port)
+	if err != nil {
+		return err
+	}
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		return errors.New(fmt.Sprintf("directory %s not empty", dir))
+	}
+	files := []struct {
+		path, tmpl string
+	}{
+		{"main.go", mainTemplate},
+		{name + ".go", speechletTemplate},
+		{name + "_test.go", testTemplate},
+		{"conf/app.json", appConfTemplate},
+		{DialogPath, dialogTemplate},
+		{"Makefile", makefileTemplate},
+		{"Dockerfile", dockerfileTemplate},
+	}
+	for _, v := range files {
+		if err := writeTemplate(filepath.Join(dir, v.path), v.tmpl, p); err != nil {
+			return err
+		}
+	}
+	dm, err := loadDialogModel(filepath.Join(dir, DialogPath))
+	if err != nil {
+		return err
+	}
+	return generateProject(dir, dm)
+}
+
+// generateProject writes the code generated from the dialog model of the
+// project in dir.
+func generateProject(dir string, dm *model.DialogModel) error {
+	src, err := Generate(dm, DialogPath, "main")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, GenPath), src, 0644)
+}
+
+func writeTemplate(path, tmpl string, data interface{}) error {
+	t, err := template.New(filepath.Base(path)).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, data); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	if strings.HasSuffix(path, ".go") {
+		if out, err = format.Source(out); err != nil {
+			return errors.New(fmt.Sprintf("%s: %s", path, err))
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, 0644)
+}
+
+// intentStub is what the case and the handler of an intent are executed
+// with.
+type intentStub struct {
+	Intent     string
+	IntentType string
+	Method     string
+	Type       string
+}
+
+// AddIntent adds the intent to the dialog model of the project in dir, each
+// slot given as name or name:type is elicited with a prompt to be reworded.
+// The speechlet gets a case and a handler stub for the intent, the method of
+// the IntentHandler generated again in GenPath.
+func AddIntent(dir, intent string, slots ...string) error {
+	intentType, method, err := intentGoNames(intent)
+	if err != nil {
+		return err
+	}
+	dm, err := loadDialogModel(filepath.Join(dir, DialogPath))
+	if err != nil {
+		return err
+	}
+	if dm.GetIntent(intent) != nil {
+		return errors.New(fmt.Sprintf("intent %s already in %s", intent, DialogPath))
+	}
+	path, src, err := findSpeechlet(dir)
+	if err != nil {
+		return err
+	}
+	mi := model.NewIntent(intent, false)
+	for _, v := range slots {
+		name, typ := v, DefaultSlotType
+		if i := strings.Index(v, ":"); i >= 0 {
+			name, typ = v[:i], v[i+1:]
+		}
+		if name == "" || typ == "" || mi.GetSlot(name) != nil {
+			return errors.New(fmt.Sprintf("slot %q invalid or repeated", v))
+		}
+		id := fmt.Sprintf("Elicit.Slot.%s.%s", intent, name)
+		mi.WithSlots(model.NewSlot(name, typ, false, true).
+			WithPrompts(model.PromptIds{Elicitation: id}))
+		value, _ := json.Marshal(fmt.Sprintf("What is the %s?", name))
+		dm.WithPrompts(&model.Prompt{ID: id, Variations: []*model.Variation{
+			{Type: "PlainText", Value: []json.RawMessage{value}}}})
+	}
+	if mi.Slots == nil {
+		mi.Slots = []*model.Slot{}
+	}
+	dm.Dialog.Intents = append(dm.Dialog.Intents, mi)
+
+	stub := &intentStub{Intent: intent, IntentType: intentType, Method: method,
+		Type: speechletType(src)}
+	if bytes.Contains(src, []byte("func (sk *"+stub.Type+") "+stub.Method+"(")) {
+		return errors.New(fmt.Sprintf("%s already has %s", path, stub.Method))
+	}
+	src, err = addIntentStub(src, stub)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%s: %s", path, err))
+	}
+	if err := generateProject(dir, dm); err != nil {
+		return err
+	}
+	if err := saveDialogModel(filepath.Join(dir, DialogPath), dm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+func loadDialogModel(path string) (*model.DialogModel, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		return nil, errors.New(fmt.Sprintf("%s: %s", path, err))
+	}
+	return dm, nil
+}
+
+func saveDialogModel(path string, dm *model.DialogModel) error {
+	if dm.Prompts == nil {
+		dm.Prompts = []*model.Prompt{}
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(dm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// findSpeechlet returns the Go file of the project holding the Marker.
+func findSpeechlet(dir string) (string, []byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	for _, v := range paths {
+		src, err := ioutil.ReadFile(v)
+		if err != nil {
+			return "", nil, err
+		}
+		if bytes.Contains(src, []byte(Marker)) {
+			return v, src, nil
+		}
+	}
+	return "", nil, errors.New(fmt.Sprintf("no Go file of %s has the marker %q", dir, Marker))
+}
+
+// speechletType returns the receiver type of the OnIntent method.
+func speechletType(src []byte) string {
+	const prefix = "func (sk *"
+	for _, line := range strings.Split(string(src), "\n") {
+		if strings.HasPrefix(line, prefix) && strings.Contains(line, ") OnIntent(") {
+			return line[len(prefix):strings.Index(line, ")")]
+		}
+	}
+	return ""
+}
+
+func addIntentStub(src []byte, stub *intentStub) ([]byte, error) {
+	if stub.Type == "" {
+		return nil, errors.New("no OnIntent method of receiver sk")
+	}
+	var caseBuf, handlerBuf bytes.Buffer
+	if err := template.Must(template.New("case").Parse(caseTemplate)).
+		Execute(&caseBuf, stub); err != nil {
+		return nil, err
+	}
+	if err := template.Must(template.New("handler").Parse(handlerTemplate)).
+		Execute(&handlerBuf, stub); err != nil {
+		return nil, err
+	}
+	i := bytes.Index(src, []byte(Marker))
+	out := append(append(append([]byte(nil), src[:i]...), caseBuf.Bytes()...), src[i:]...)
+	out = append(out, handlerBuf.Bytes()...)
+	return format.Source(out)
+}
@@ -0,0 +1,148 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+func TestNewAndAddIntent(t *testing.T) {
+	root, err := ioutil.TempDir("", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "plan-my-trip")
+	if err := New(dir, "plan-my-trip", DefaultPort); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"main.go", "plan-my-trip.go", "plan-my-trip_test.go",
+		"conf/app.json", DialogPath, GenPath, "Makefile", "Dockerfile"} {
+		if _, err := os.Stat(filepath.Join(dir, v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := New(dir, "plan-my-trip", DefaultPort); err == nil {
+		t.Fatal("want error on a directory not empty")
+	}
+
+	if err := AddIntent(dir, "PlanMyTrip", "toCity:ROSAI.CITY", "travelDate"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddIntent(dir, "PlanMyTrip"); err == nil {
+		t.Fatal("want error on an intent added twice")
+	}
+	dm, err := loadDialogModel(filepath.Join(dir, DialogPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := dm.GetIntent("PlanMyTrip")
+	if mi == nil || len(mi.Slots) != 2 || mi.GetSlot("travelDate").Type != DefaultSlotType ||
+		!dm.Verify() {
+		t.Fatalf("want PlanMyTrip with its prompts, got: %+v", dm)
+	}
+	src, err := ioutil.ReadFile(filepath.Join(dir, "plan-my-trip.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"case \"PlanMyTrip\":\n\t\treturn sk.OnPlanMyTrip(request, " +
+			"NewPlanMyTripIntent(request.Intent))\n\t" + Marker,
+		"func (sk *PlanMyTrip) OnPlanMyTrip(request *sp.IntentRequest, intent *PlanMyTripIntent) (",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("want %q in the speechlet, got:\n%s", want, src)
+		}
+	}
+	// the stub is the method of the generated IntentHandler
+	gen, err := ioutil.ReadFile(filepath.Join(dir, GenPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"OnPlanMyTrip(request *sp.IntentRequest, intent *PlanMyTripIntent) (*sp.Response, *sp.Context, error)",
+		"TravelDate: intent.GetSlot(SlotTravelDate).StringOrDefault(\"\"),",
+	} {
+		if !strings.Contains(string(gen), want) {
+			t.Fatalf("want %q in %s, got:\n%s", want, GenPath, gen)
+		}
+	}
+	for _, v := range []string{"main.go", "plan-my-trip.go", "plan-my-trip_test.go", GenPath} {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, v), nil,
+			0); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNewInvalidName(t *testing.T) {
+	root, err := ioutil.TempDir("", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	for _, v := range []string{"", "PlanMyTrip", "plan_my_trip", "../trip", "trip/x",
+		"trip.v2", "--"} {
+		dir := filepath.Join(root, "trip")
+		if err := New(dir, v, DefaultPort); err == nil {
+			t.Fatalf("want error on skill name %q", v)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Fatalf("want nothing written for skill name %q", v)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for in, want := range map[string]string{
+		"planmytrip":       "Planmytrip",
+		"plan-my-trip":     "PlanMyTrip",
+		"ROSAI.HelpIntent": "ROSAIHelpIntent",
+		"2go":              "Go",
+		"--":               "",
+	} {
+		if got := goName(in); got != want {
+			t.Fatalf("goName(%q): want %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := New(dir, "trip", DefaultPort); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddIntent(dir, "PlanMyTrip", "toCity", "date"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, DialogPath)
+	dm, _ := loadDialogModel(path)
+	dm.GetSlot("PlanMyTrip", "toCity").Handler = "CheckCity"
+	dm.GetSlot("PlanMyTrip", "date").Handler = "CheckDate"
+	if err := saveDialogModel(path, dm); err != nil {
+		t.Fatal(err)
+	}
+	handler := "package main\n\ntype Slots struct{}\n\n" +
+		"func (s *Slots) CheckCity() string { return \"\" }\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "slots.go"), []byte(handler), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := Lint(path, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Code != model.LintUnknownHandler ||
+		!strings.Contains(issues[0].Message, "CheckDate") {
+		t.Fatalf("want CheckDate unknown, got: %v", issues)
+	}
+}
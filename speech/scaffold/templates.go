@@ -0,0 +1,212 @@
+package scaffold
+
+// The files of a new skill, executed with a *Project. The speechlet file
+// keeps the marker of add-intent in its switch.
+
+const mainTemplate = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/util"
+)
+
+const (
+	AppId      = "{{.AppId}}"
+	Route      = "/{{.Name}}"
+	ConfPath   = "./conf/app.json"
+	DialogPath = "./conf/dialog.json"
+)
+
+func main() {
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.Lshortfile)
+	conf, err := util.InitSpecConf(ConfPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rh, err := NewRequestHandler(DialogPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the sessions are kept in memory when no redis is configured
+	if addr, _ := util.GetSpecCfgVal(conf, "", "redis", "addr"); addr == "" {
+		log.Println("INFO] redis unset, sessions kept in memory")
+		rh.SessionStore = sp.NewMemorySessionStore()
+	}
+	host, _ := util.GetSpecCfgVal(conf, "0.0.0.0", "server", "host")
+	port, _ := util.GetSpecCfgVal(conf, {{.Port}}, "server", "port")
+	http.Handle(Route, rh)
+	srv := &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", host, port),
+		ReadTimeout:  100 * time.Millisecond,
+		WriteTimeout: 3000 * time.Millisecond,
+		IdleTimeout:  90 * time.Second,
+	}
+	log.Printf("INFO] start {{.Name}} server on: %s%s", srv.Addr, Route)
+	log.Fatal(srv.ListenAndServe())
+}
+
+// NewRequestHandler makes the handler of the skill with the dialog model.
+func NewRequestHandler(dialogPath string) (*sp.RequestHandler, error) {
+	data, err := ioutil.ReadFile(dialogPath)
+	if err != nil {
+		return nil, err
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		return nil, err
+	}
+	return &sp.RequestHandler{
+		AppId:       AppId,
+		Speechlet:   &{{.Type}}{},
+		DialogModel: dm,
+	}, nil
+}
+`
+
+const speechletTemplate = `package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/directives"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+//go:generate rosai-skill gen -o dialog_gen.go conf/dialog.json
+
+// {{.Type}} is the speechlet of the skill, an IntentHandler with a method
+// per intent of the dialog model.
+type {{.Type}} struct {
+}
+
+func (sk *{{.Type}}) OnSessionStarted(re *sp.RequestEnvelope) error {
+	log.Printf("INFO] OnSessionStarted requestId=%s", re.Request.GetRequestId())
+	return nil
+}
+
+func (sk *{{.Type}}) OnLaunch(re *sp.RequestEnvelope) (*sp.Response, error) {
+	return sp.NewAskResponse("{{.Welcome}}"), nil
+}
+
+func (sk *{{.Type}}) OnIntent(re *sp.RequestEnvelope) (*sp.Response, *sp.Context, error) {
+	request, ok := re.Request.(*sp.IntentRequest)
+	if !ok {
+		return nil, nil, errors.New("OnIntent assert requestEnvelope for IntentRequest failed")
+	}
+	// the dialog model elicits the slots until the intent is completed
+	if request.DialogState != slu.COMPLETED {
+		return sp.NewDelegateResponse([]directives.Directive{
+			directives.NewDelegateDirective(request.Intent)}), nil, nil
+	}
+	switch name := request.IntentName(); name {
+	{{.Marker}}
+	default:
+		tip := fmt.Sprintf("Intent(%s) is unsupported. Please try something else.", name)
+		return sp.NewAskResponse(tip), nil, nil
+	}
+}
+
+func (sk *{{.Type}}) OnSessionEnded(re *sp.RequestEnvelope) error {
+	log.Printf("INFO] OnSessionEnded requestId=%s", re.Request.GetRequestId())
+	return nil
+}
+`
+
+const testTemplate = `package main
+
+import (
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/skilltest"
+)
+
+func TestLaunch(t *testing.T) {
+	rh, err := NewRequestHandler(DialogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skilltest.New(t, rh).Launch().ExpectAsk("{{.Welcome}}")
+}
+`
+
+const appConfTemplate = `{
+  "server": {
+    "host": "0.0.0.0",
+    "port": {{.Port}}
+  },
+  "redis": {
+    "addr": "",
+    "passwd": "",
+    "db": "5"
+  }
+}
+`
+
+const dialogTemplate = `{
+  "dialog": {
+    "intents": []
+  },
+  "prompts": []
+}
+`
+
+const makefileTemplate = `NAME := {{.Name}}
+PORT := {{.Port}}
+
+.PHONY: build test run sim docker
+
+build:
+	go build -o bin/$(NAME) .
+
+test:
+	go test ./...
+
+run: build
+	./bin/$(NAME)
+
+# talks to the skill started by make run
+sim:
+	go run roobo.com/rosai-skills-kit-sdk-for-go/cmd/rosai-sim \
+		-dialog conf/dialog.json -url http://localhost:$(PORT)/$(NAME)
+
+docker:
+	docker build -t $(NAME) .
+`
+
+const dockerfileTemplate = `FROM golang:1.12 AS build
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /{{.Name}} .
+
+FROM alpine:3.9
+WORKDIR /app
+COPY --from=build /{{.Name}} /app/{{.Name}}
+COPY conf /app/conf
+EXPOSE {{.Port}}
+CMD ["/app/{{.Name}}"]
+`
+
+// caseTemplate and handlerTemplate are added by AddIntent, executed with an
+// *intentStub.
+const caseTemplate = `case "{{.Intent}}":
+		return sk.{{.Method}}(request, New{{.IntentType}}(request.Intent))
+	`
+
+const handlerTemplate = `
+// {{.Method}} handles {{.Intent}} once its slots are collected.
+func (sk *{{.Type}}) {{.Method}}(request *sp.IntentRequest, intent *{{.IntentType}}) (
+	*sp.Response, *sp.Context, error) {
+	return sp.NewTellResponse("{{.Intent}} is not implemented yet"), nil, nil
+}
+`
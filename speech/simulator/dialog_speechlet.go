@@ -0,0 +1,52 @@
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/directives"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+// DialogSpeechlet is a skill made of its dialog model alone: it delegates
+// the dialogs to the model, then says the values of the slots collected. It
+// lets the dialog.json of a skill be tried before any code is written.
+type DialogSpeechlet struct {
+	Welcome string
+}
+
+func (ds *DialogSpeechlet) OnSessionStarted(re *sp.RequestEnvelope) error {
+	return nil
+}
+
+func (ds *DialogSpeechlet) OnLaunch(re *sp.RequestEnvelope) (*sp.Response, error) {
+	welcome := ds.Welcome
+	if welcome == "" {
+		welcome = "Welcome, what can I do for you?"
+	}
+	return sp.NewAskResponse(welcome), nil
+}
+
+func (ds *DialogSpeechlet) OnIntent(re *sp.RequestEnvelope) (*sp.Response, *sp.Context, error) {
+	req := re.Request.(*sp.IntentRequest)
+	intent := req.GetIntent()
+	if req.DialogState != slu.COMPLETED {
+		return sp.NewDelegateResponse([]directives.Directive{
+			directives.NewDelegateDirective(intent)}), nil, nil
+	}
+	var slots []string
+	for k, v := range intent.Slots {
+		if v.HasValue() {
+			slots = append(slots, fmt.Sprintf("%s=%s", k, v.GetStringValue()))
+		}
+	}
+	sort.Strings(slots)
+	return sp.NewTellResponse(fmt.Sprintf("%s done: %s", intent.Name,
+		strings.Join(slots, ", "))), nil, nil
+}
+
+func (ds *DialogSpeechlet) OnSessionEnded(re *sp.RequestEnvelope) error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+// printResponse prints what the device would say and show.
+func (s *Simulator) printResponse(respEn *sp.ResponseEnvelopeRaw) {
+	if st := respEn.Status; st != nil && st.Code != sp.ApiSuccess {
+		fmt.Fprintf(s.out, "  status: %d %s %s\n", st.Code, st.ErrorType, st.ErrorDetails)
+	}
+	var results []*sp.Result
+	if len(respEn.Results) > 0 {
+		results = respEn.GetResults()
+	}
+	for _, r := range results {
+		if text := speechText(r.GetOutputSpeech()); text != "" {
+			fmt.Fprintf(s.out, "  speech: %s\n", text)
+		}
+		if hint := r.GetHint(); hint != "" {
+			fmt.Fprintf(s.out, "  hint: %s\n", hint)
+		}
+		for _, v := range r.GetDirectives() {
+			dd, ok := v.(*sp.DisplayDirective)
+			if !ok {
+				fmt.Fprintf(s.out, "  directive: %s\n", v.GetType())
+				continue
+			}
+			if card := dd.GetCard(); card != nil {
+				fmt.Fprintf(s.out, "  card: %s\n", ui.CardText(card))
+			}
+			if hint := dd.GetHint(); hint != "" {
+				fmt.Fprintf(s.out, "  hint: %s\n", hint)
+			}
+		}
+		if ss := r.GetSuggestions(); len(ss) > 0 {
+			texts := make([]string, 0, len(ss))
+			for _, v := range ss {
+				texts = append(texts, v.Text)
+			}
+			fmt.Fprintf(s.out, "  suggestions: %s\n", strings.Join(texts, " | "))
+		}
+	}
+	if rp := respEn.Reprompt; rp != nil {
+		if text := speechText(rp.GetOutputSpeech()); text != "" {
+			fmt.Fprintf(s.out, "  reprompt: %s\n", text)
+		}
+	}
+}
+
+// speechText reads the speech items as plain text.
+func speechText(items *sp.SpeechItems) string {
+	if items == nil {
+		return ""
+	}
+	var texts []string
+	for _, v := range items.Items {
+		text := v.GetSource()
+		if v.GetType() == ui.SSMLType {
+			if plain, err := ui.SSMLToPlainText(text); err == nil {
+				text = plain
+			}
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,330 @@
+// Package simulator lets a developer talk to a skill from a terminal, turn by
+// turn, without the ROSAI platform in front of it:
+//
+//	> launch
+//	> intent PlanMyTrip toCity=Paris "travelDate=next monday"
+//	> yes
+//	> end
+//
+// The skill is either a RequestHandler called in-process, whose sessions are
+// then kept in memory, or a RequestHandler served at a URL. After each turn
+// the speech, cards and directives of the response are printed, with what
+// changed in the context and, in-process, in the session.
+package simulator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/fixtures"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+const Prompt = "> "
+
+type Simulator struct {
+	driver *fixtures.Driver
+	model  *model.DialogModel
+	out    io.Writer
+
+	// dialog is the intent of the dialog in progress as sent by the user,
+	// used when the session can not be seen.
+	dialog  *slu.Intent
+	params  map[string]string
+	session map[string]string
+}
+
+// New simulates the skill of a copy of the handler whose sessions are kept
+// in memory.
+func New(handler *sp.RequestHandler) *Simulator {
+	return &Simulator{
+		driver: fixtures.NewDriver(handler),
+		model:  handler.DialogModel,
+		out:    os.Stdout,
+	}
+}
+
+// NewRemote simulates the skill served at the url, e.g.
+// http://localhost:10000/planmytrip.
+func NewRemote(url string) *Simulator {
+	return &Simulator{driver: fixtures.NewRemoteDriver(url), out: os.Stdout}
+}
+
+// WithModel gives the dialog model of the skill, needed by yes and no to
+// know what is confirmed.
+func (s *Simulator) WithModel(dm *model.DialogModel) *Simulator {
+	s.model = dm
+	return s
+}
+
+func (s *Simulator) WithOutput(w io.Writer) *Simulator {
+	s.out = w
+	return s
+}
+
+// Requests returns the builder of the requests sent, e.g. to change the
+// device.
+func (s *Simulator) Requests() *fixtures.Builder {
+	return s.driver.Requests()
+}
+
+// Run reads the commands from in until it ends or the user quits.
+func (s *Simulator) Run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for fmt.Fprint(s.out, Prompt); scanner.Scan(); fmt.Fprint(s.out, Prompt) {
+		quit, err := s.Exec(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %s\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+	fmt.Fprintln(s.out)
+	return scanner.Err()
+}
+
+// Exec runs one command, quit tells whether the user quits.
+func (s *Simulator) Exec(line string) (quit bool, err error) {
+	args, err := splitArgs(line)
+	if err != nil || len(args) == 0 {
+		return false, err
+	}
+	var reqEn *sp.RequestEnvelope
+	switch cmd := strings.ToLower(args[0]); cmd {
+	default:
+		return false, errors.New(fmt.Sprintf("unknown command %s, try help", args[0]))
+	case "help":
+		fmt.Fprint(s.out, Usage)
+		return false, nil
+	case "quit", "exit":
+		return true, nil
+	case "launch":
+		reqEn = s.driver.Requests().Launch()
+	case "intent":
+		if len(args) < 2 {
+			return false, errors.New("usage: intent NAME [SLOT=VALUE ...]")
+		}
+		intent, err := parseIntent(args[1], args[2:])
+		if err != nil {
+			return false, err
+		}
+		reqEn = s.driver.Requests().Intent(intent)
+	case "yes", "no":
+		status := slu.CONFIRMED
+		if cmd == "no" {
+			status = slu.DENIED
+		}
+		intent, err := s.confirm(status)
+		if err != nil {
+			return false, err
+		}
+		reqEn = s.driver.Requests().Intent(intent)
+	case "end":
+		reqEn = s.driver.Requests().SessionEnded(sp.USER_INITIATED)
+	}
+	return false, s.send(reqEn)
+}
+
+const Usage = `commands:
+  launch                          open the skill
+  intent NAME [SLOT=VALUE ...]    say the intent with the values of the slots
+  yes, no                         confirm or deny what the skill asked to confirm
+  end                             end the session as the user would
+  help                            show this help
+  quit                            leave the simulator
+`
+
+// splitArgs splits the line on spaces, except within double quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted, inArg = !quoted, true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+			}
+			inArg = false
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// parseIntent makes the intent as the fixtures do, the values said being the
+// origins read by the prompts.
+func parseIntent(name string, slots []string) (*slu.Intent, error) {
+	values := make(map[string]string, len(slots))
+	for _, v := range slots {
+		i := strings.Index(v, "=")
+		if i <= 0 {
+			return nil, errors.New(fmt.Sprintf("slot %q not in the form SLOT=VALUE", v))
+		}
+		values[v[:i]] = v[i+1:]
+	}
+	return fixtures.NewIntent(name, values), nil
+}
+
+// confirm makes the intent confirming or denying the first slot, or else the
+// intent, of the dialog in progress which waits for a confirmation.
+func (s *Simulator) confirm(status slu.ConfirmationStatus) (*slu.Intent, error) {
+	if s.model == nil {
+		return nil, errors.New("yes and no need the dialog model of the skill")
+	}
+	dialog := s.dialogIntent()
+	if dialog == nil {
+		return nil, errors.New("no dialog in progress to confirm")
+	}
+	mi := s.model.GetIntent(dialog.Name)
+	if mi == nil {
+		return nil, errors.New(fmt.Sprintf("intent %s not in the dialog model", dialog.Name))
+	}
+	for _, v := range mi.Slots {
+		if v.NeedElicit() && dialog.CanElicit(v.Name) {
+			return nil, errors.New(fmt.Sprintf("slot %s of %s is to be said, not confirmed",
+				v.Name, dialog.Name))
+		}
+		if v.NeedConfirm() && dialog.CanConfirm(v.Name) {
+			slot := *dialog.GetSlot(v.Name)
+			slot.ConfirmationStatus = status
+			return slu.NewIntent(dialog.Name).WithSlot(&slot), nil
+		}
+	}
+	if mi.NeedConfirm() && dialog.ConfirmationStatus == slu.NONE {
+		return slu.NewIntent(dialog.Name).WithStatus(status), nil
+	}
+	return nil, errors.New(fmt.Sprintf("nothing of %s to confirm", dialog.Name))
+}
+
+// dialogIntent returns the intent of the dialog in progress, from the
+// session when it can be seen.
+func (s *Simulator) dialogIntent() *slu.Intent {
+	if ss := s.fetchSession(); ss != nil {
+		dsm := ss.GetDialogStateMachine()
+		if !dsm.Active() {
+			return nil
+		}
+		return ss.GetUpdatedIntent(dsm.IntentName)
+	}
+	return s.dialog
+}
+
+func (s *Simulator) fetchSession() *sp.Session {
+	if !s.driver.InProcess() {
+		return nil
+	}
+	ss, err := s.driver.Session()
+	if err != nil {
+		return nil
+	}
+	return ss
+}
+
+// send sends the request as the next turn and prints the response.
+func (s *Simulator) send(reqEn *sp.RequestEnvelope) error {
+	respEn, err := s.driver.Send(reqEn)
+	if err != nil {
+		return err
+	}
+	s.trackDialog(reqEn.Request)
+	s.printResponse(respEn)
+	s.printDiff("context", &s.params, contextParams(respEn.Context))
+	if s.driver.InProcess() {
+		s.printDiff("session", &s.session, sessionAttributes(s.fetchSession()))
+	}
+	if reqEn.Request.GetType() == sp.SessionEndedRequestType || s.driver.Ended() {
+		fmt.Fprintln(s.out, "(session ended)")
+		s.dialog = nil
+		s.driver.DropSession()
+		s.session = nil
+	}
+	return nil
+}
+
+func (s *Simulator) trackDialog(req sp.Request) {
+	intReq, ok := req.(*sp.IntentRequest)
+	if !ok {
+		return
+	}
+	intent := intReq.GetIntent()
+	if s.dialog == nil || s.dialog.Name != intent.Name {
+		s.dialog = intent.Clone()
+		return
+	}
+	s.dialog.Merge(intent)
+}
+
+func contextParams(ctx *sp.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	params := make(map[string]string, len(ctx.CtxParams))
+	for k, v := range ctx.CtxParams {
+		if str, err := ctx.CtxParams.AsString(k); err == nil {
+			params[k] = strconv.Quote(str)
+			continue
+		}
+		params[k] = fixtures.Render(v)
+	}
+	return params
+}
+
+func sessionAttributes(ss *sp.Session) map[string]string {
+	if ss == nil {
+		return nil
+	}
+	attrs := make(map[string]string, len(ss.Attributes))
+	for k, v := range ss.Attributes {
+		attrs[k] = fixtures.Render(v)
+	}
+	return attrs
+}
+
+// printDiff prints the keys added, removed and changed since the last turn,
+// then keeps the new values.
+func (s *Simulator) printDiff(name string, last *map[string]string, now map[string]string) {
+	keys := make([]string, 0, len(now)+len(*last))
+	for k := range now {
+		keys = append(keys, k)
+	}
+	for k := range *last {
+		if _, ok := now[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		before, was := (*last)[k]
+		after, is := now[k]
+		switch {
+		case !was:
+			fmt.Fprintf(s.out, "  %s + %s = %s\n", name, k, after)
+		case !is:
+			fmt.Fprintf(s.out, "  %s - %s\n", name, k)
+		case before != after:
+			fmt.Fprintf(s.out, "  %s ~ %s = %s (was %s)\n", name, k, after, before)
+		}
+	}
+	*last = now
+}
@@ -0,0 +1,121 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+func newDialogHandler(t *testing.T) *sp.RequestHandler {
+	data, err := ioutil.ReadFile("../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		t.Fatal(err)
+	}
+	return &sp.RequestHandler{Speechlet: &DialogSpeechlet{}, DialogModel: dm}
+}
+
+func TestSimulator(t *testing.T) {
+	handler := newDialogHandler(t)
+	handler.DialogModel.GetSlot("PlanMyTrip", "toCity").ConfirmationRequired = true
+	out := new(bytes.Buffer)
+	sim := New(handler).WithOutput(out)
+	in := strings.Join([]string{
+		"launch",
+		`intent PlanMyTrip "travelDate=2018-04-11" fromCity=Beijing`,
+		"intent PlanMyTrip toCity=Paris",
+		"yes",
+		"quit",
+		"launch",
+	}, "\n")
+	if err := sim.Run(strings.NewReader(in)); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"speech: Welcome, what can I do for you?",
+		`context + travelDate = "2018-04-11"`,
+		"session + ",
+		"PlanMyTrip done: fromCity=Beijing, toCity=Paris, travelDate=2018-04-11",
+		"(session ended)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("want output containing %q, got:\n%s", want, got)
+		}
+	}
+	// the commands after quit are not run
+	if strings.Count(got, "Welcome") != 1 {
+		t.Fatalf("want one launch, got:\n%s", got)
+	}
+}
+
+func TestSimulatorConfirm(t *testing.T) {
+	sim := New(newDialogHandler(t)).WithOutput(ioutil.Discard)
+	if _, err := sim.Exec("yes"); err == nil {
+		t.Fatal("want error confirming without dialog")
+	}
+	sim.Exec("intent PlanMyTrip toCity=Paris")
+	if _, err := sim.Exec("no"); err == nil || !strings.Contains(err.Error(), "travelDate") {
+		t.Fatalf("want error on the slot to be said, got: %v", err)
+	}
+	if _, err := sim.Exec("dance"); err == nil {
+		t.Fatal("want error on unknown command")
+	}
+}
+
+func TestSimulatorSlotPlaceholders(t *testing.T) {
+	handler := newDialogHandler(t)
+	prompt := handler.DialogModel.GetSlotElicit("PlanMyTrip", "travelDate")
+	prompt.Variations[0].Value = []json.RawMessage{json.RawMessage(`"When to {$toCity}?"`)}
+	prompt.Reprompts = []*model.Variation{{Type: "PlainText",
+		Value: []json.RawMessage{json.RawMessage(`"Sorry, when to {$toCity}?"`)}}}
+	out := new(bytes.Buffer)
+	sim := New(handler).WithOutput(out)
+	if _, err := sim.Exec("intent PlanMyTrip toCity=Paris"); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{"speech: When to Paris?", "reprompt: Sorry, when to Paris?"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("want output containing %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestSimulatorRemote(t *testing.T) {
+	handler := newDialogHandler(t)
+	handler.SessionStore = sp.NewMemorySessionStore()
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	out := new(bytes.Buffer)
+	sim := NewRemote(srv.URL).WithOutput(out)
+	if _, err := sim.Exec("intent PlanMyTrip toCity=Paris"); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.Contains(got, `context + toCity = "Paris"`) {
+		t.Fatalf("want toCity in the context, got:\n%s", got)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`intent  PlanMyTrip "toCity=New York" a=b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"intent", "PlanMyTrip", "toCity=New York", "a=b"}
+	if strings.Join(args, "|") != strings.Join(want, "|") {
+		t.Fatalf("want %q, got %q", want, args)
+	}
+	if _, err := splitArgs(`intent "a`); err == nil {
+		t.Fatal("want error on unterminated quote")
+	}
+}
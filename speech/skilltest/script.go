@@ -0,0 +1,184 @@
+package skilltest
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/fixtures"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+var update = flag.Bool("skilltest.update", false, "rewrite the golden files of the conversation scripts")
+
+// Script is a conversation written as data, in YAML or JSON:
+//
+//	name: plan a trip
+//	turns:
+//	  - request: LaunchRequest
+//	    expect: {speech: "^Where do you want to go", shouldEndSession: false}
+//	  - intent: PlanMyTrip
+//	    slots: {travelDate: "2018-04-11"}
+//	    expect: {slotElicit: toCity, context: {travelDate: "2018-04-11"}}
+//
+// The responses of the turns are also compared with the golden file of the
+// script, see RunScript.
+type Script struct {
+	Name  string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Turns []*Turn `json:"turns" yaml:"turns"`
+	// Ignore are the fields left out of the golden file, in addition to
+	// fixtures.VolatileFields.
+	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+}
+
+// Turn is a request of the user and what to expect from the response. The
+// request is an IntentRequest unless another type is given.
+type Turn struct {
+	Request sp.RequestType `json:"request,omitempty" yaml:"request,omitempty"`
+	Intent  string         `json:"intent,omitempty" yaml:"intent,omitempty"`
+	Slots   Slots          `json:"slots,omitempty" yaml:"slots,omitempty"`
+	Expect  *Expectation   `json:"expect,omitempty" yaml:"expect,omitempty"`
+}
+
+// Expectation checks the response of a turn, the empty fields are not
+// checked. Speech is a regular expression.
+type Expectation struct {
+	Status           *sp.ApiStatusCode  `json:"status,omitempty" yaml:"status,omitempty"`
+	Speech           string             `json:"speech,omitempty" yaml:"speech,omitempty"`
+	ShouldEndSession *bool              `json:"shouldEndSession,omitempty" yaml:"shouldEndSession,omitempty"`
+	Context          map[string]string  `json:"context,omitempty" yaml:"context,omitempty"`
+	Directives       []sp.DirectiveType `json:"directives,omitempty" yaml:"directives,omitempty"`
+	SlotElicit       string             `json:"slotElicit,omitempty" yaml:"slotElicit,omitempty"`
+}
+
+// LoadScript reads a script, as YAML when its extension is .yaml or .yml
+// and as JSON otherwise.
+func LoadScript(path string) (*Script, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	script := new(Script)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, script)
+	default:
+		err = json.Unmarshal(data, script)
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("script %s: %s", path, err))
+	}
+	for i, v := range script.Turns {
+		if v.Request == "" && v.Intent == "" {
+			return nil, errors.New(fmt.Sprintf("script %s: turn %d has neither request "+
+				"type nor intent", path, i+1))
+		}
+	}
+	return script, nil
+}
+
+// RunScripts runs the scripts matching the pattern as subtests, the golden
+// files are skipped.
+func RunScripts(t *testing.T, handler *sp.RequestHandler, pattern string) {
+	t.Helper()
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts := paths[:0]
+	for _, v := range paths {
+		if !strings.HasSuffix(v, goldenSuffix) {
+			scripts = append(scripts, v)
+		}
+	}
+	if len(scripts) == 0 {
+		t.Fatalf("no script matches %s", pattern)
+	}
+	for _, v := range scripts {
+		path := v
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			RunScript(t, handler, path)
+		})
+	}
+}
+
+// RunScript runs the script in a new conversation, then compares the
+// responses with the golden file next to it, e.g. trip.golden.json for
+// trip.yaml. Run the tests with -skilltest.update to write the golden files.
+func RunScript(t *testing.T, handler *sp.RequestHandler, path string) {
+	t.Helper()
+	script, err := LoadScript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New(t, handler)
+	norm := fixtures.NewNormalizer(handler.DialogModel, script.Ignore...)
+	responses := make([]interface{}, 0, len(script.Turns))
+	for _, v := range script.Turns {
+		c.SendEnvelope(v.makeRequest(c))
+		v.Expect.check(c)
+		responses = append(responses, norm.Normalize(c.Response()))
+	}
+	checkGolden(t, goldenPath(path), responses)
+}
+
+func (turn *Turn) makeRequest(c *Conversation) *sp.RequestEnvelope {
+	switch turn.Request {
+	case sp.LaunchRequestType:
+		return c.requests.Launch()
+	case sp.SessionEndedRequestType:
+		return c.requests.SessionEnded(sp.USER_INITIATED)
+	case "", sp.IntentRequestType:
+	default:
+		c.t.Fatalf("request type %s not supported by scripts", turn.Request)
+	}
+	return c.requests.IntentWithSlots(turn.Intent, turn.Slots)
+}
+
+func (e *Expectation) check(c *Conversation) {
+	c.t.Helper()
+	if e == nil {
+		return
+	}
+	if e.Status != nil {
+		c.ExpectStatus(*e.Status)
+	}
+	if e.Speech != "" {
+		c.ExpectSpeechMatches(e.Speech)
+	}
+	if e.ShouldEndSession != nil && c.Ended() != *e.ShouldEndSession {
+		c.t.Fatalf("%s: want shouldEndSession %t", c.turn(), *e.ShouldEndSession)
+	}
+	for k, v := range e.Context {
+		c.ExpectParameter(k, v)
+	}
+	for _, v := range e.Directives {
+		c.ExpectDirective(v)
+	}
+	if e.SlotElicit != "" {
+		c.ExpectSlotElicit(e.SlotElicit)
+	}
+}
+
+// ExpectSpeechMatches checks the speech of the turn against the regular
+// expression.
+func (c *Conversation) ExpectSpeechMatches(pattern string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		c.t.Fatalf("%s: %s", c.turn(), err)
+	}
+	if got := c.Speech(); !re.MatchString(got) {
+		c.t.Fatalf("%s: want speech matching %q, got: %q", c.turn(), pattern, got)
+	}
+	return c
+}
@@ -0,0 +1,327 @@
+// Package skilltest drives a RequestHandler in-process to test a skill turn
+// by turn, without an HTTP server nor redis:
+//
+//	skilltest.New(t, handler).
+//		Launch().ExpectAsk("Welcome, where do you want to go?").
+//		Say("PlanMyTrip", skilltest.Slots{"travelDate": "2018-04-11"}).
+//		ExpectSlotElicit("toCity").
+//		Say("PlanMyTrip", skilltest.Slots{"toCity": "Seattle"}).
+//		ExpectParameter("toCity", "Seattle").ExpectEnd()
+//
+// Each expectation fails the test at once, as the turns after it would not
+// make sense anymore.
+package skilltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/fixtures"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+const (
+	UserId   = "skilltest.user"
+	AppId    = "skilltest.app"
+	DeviceId = "skilltest.device"
+	SkillId  = "skilltest.skill"
+)
+
+// Slots are the string values of the slots said by the user.
+type Slots map[string]string
+
+// Conversation is a conversation of a user with the skill, its expectations
+// apply to the last turn.
+type Conversation struct {
+	t        testing.TB
+	driver   *fixtures.Driver
+	requests *fixtures.Builder
+
+	// the last turn
+	request  sp.Request
+	response *sp.ResponseEnvelopeRaw
+	results  []*sp.Result
+	before   *sp.DialogStateMachine
+	after    *sp.DialogStateMachine
+}
+
+// New starts a conversation with a copy of the handler whose sessions are
+// kept in memory.
+func New(t testing.TB, handler *sp.RequestHandler) *Conversation {
+	c := &Conversation{t: t}
+	c.requests = fixtures.New().WithUser(UserId, AppId).WithSkill(SkillId).
+		WithDeviceId(DeviceId)
+	c.driver = fixtures.NewDriver(handler).WithRequests(c.requests)
+	return c
+}
+
+// WithDevice makes the requests come from the device, e.g. to declare its
+// supported interfaces.
+func (c *Conversation) WithDevice(dev *sp.Device) *Conversation {
+	c.requests.WithDevice(dev)
+	return c
+}
+
+// Requests returns the builder of the requests sent, whose context holds the
+// parameters returned by the last turn.
+func (c *Conversation) Requests() *fixtures.Builder {
+	return c.requests
+}
+
+func (c *Conversation) Launch() *Conversation {
+	return c.SendEnvelope(c.requests.Launch())
+}
+
+// Say sends an IntentRequest of the intent with the slots.
+func (c *Conversation) Say(intent string, slots Slots) *Conversation {
+	return c.SayIntent(fixtures.NewIntent(intent, slots))
+}
+
+func (c *Conversation) SayIntent(intent *slu.Intent) *Conversation {
+	return c.SendEnvelope(c.requests.Intent(intent))
+}
+
+// End ends the session as the user would.
+func (c *Conversation) End() *Conversation {
+	return c.SendEnvelope(c.requests.SessionEnded(sp.USER_INITIATED))
+}
+
+// Send sends the request as the next turn.
+func (c *Conversation) Send(req sp.Request) *Conversation {
+	c.t.Helper()
+	return c.SendEnvelope(c.requests.Envelope(req))
+}
+
+// SendEnvelope sends the request envelope as the next turn.
+func (c *Conversation) SendEnvelope(reqEn *sp.RequestEnvelope) *Conversation {
+	c.t.Helper()
+	req := reqEn.Request
+	c.request = req
+	c.before = c.dialogState()
+	respEn, err := c.driver.Send(reqEn)
+	if err != nil {
+		c.t.Fatalf("%s: %s", req.GetType(), err)
+	}
+	c.response = respEn
+	c.results = nil
+	if len(c.response.Results) > 0 {
+		c.results = c.response.GetResults()
+	}
+	c.after = c.dialogState()
+	if c.Ended() {
+		c.driver.DropSession()
+	}
+	return c
+}
+
+func (c *Conversation) dialogState() *sp.DialogStateMachine {
+	dsm := *c.Session().GetDialogStateMachine()
+	attempts := make(map[string]int, len(dsm.Attempts))
+	for k, v := range dsm.Attempts {
+		attempts[k] = v
+	}
+	dsm.Attempts = attempts
+	return &dsm
+}
+
+// Session returns the session as saved after the last turn.
+func (c *Conversation) Session() *sp.Session {
+	ss, err := c.driver.Session()
+	if err != nil {
+		c.t.Fatalf("fetch session: %s", err)
+	}
+	return ss
+}
+
+// Response returns the response of the last turn.
+func (c *Conversation) Response() *sp.ResponseEnvelopeRaw {
+	return c.response
+}
+
+func (c *Conversation) Results() []*sp.Result {
+	return c.results
+}
+
+// Ended reports whether the last turn ended the session.
+func (c *Conversation) Ended() bool {
+	return c.driver.Ended()
+}
+
+// Speech returns the text said by the last turn, SSML read as plain text.
+func (c *Conversation) Speech() string {
+	var texts []string
+	for _, r := range c.results {
+		if r.GetOutputSpeech() == nil {
+			continue
+		}
+		for _, v := range r.GetOutputSpeech().Items {
+			switch v.GetType() {
+			case ui.PlainTextType:
+				texts = append(texts, v.GetSource())
+			case ui.SSMLType:
+				text, err := ui.SSMLToPlainText(v.GetSource())
+				if err != nil {
+					c.t.Fatalf("%s: %s", c.turn(), err)
+				}
+				texts = append(texts, text)
+			}
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// Directive returns the first directive of the type in the last turn.
+func (c *Conversation) Directive(typ sp.DirectiveType) sp.Directive {
+	for _, r := range c.results {
+		for _, v := range r.GetDirectives() {
+			if v.GetType() == typ {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Conversation) turn() string {
+	if c.request == nil {
+		return "no turn"
+	}
+	return fmt.Sprintf("turn %s %s", c.request.GetRequestId(), c.request.GetType())
+}
+
+func (c *Conversation) ExpectStatus(code sp.ApiStatusCode) *Conversation {
+	c.t.Helper()
+	if got := c.response.Status; got == nil || got.Code != code {
+		c.t.Fatalf("%s: want status %d, got: %+v", c.turn(), code, got)
+	}
+	return c
+}
+
+func (c *Conversation) expectOK() {
+	c.t.Helper()
+	if c.response == nil {
+		c.t.Fatal("no turn to expect from")
+	}
+	if st := c.response.Status; st != nil && st.Code != sp.ApiSuccess {
+		c.t.Fatalf("%s: want status ok, got: %+v", c.turn(), st)
+	}
+}
+
+// ExpectSpeech checks the whole speech of the turn.
+func (c *Conversation) ExpectSpeech(text string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	if got := c.Speech(); got != text {
+		c.t.Fatalf("%s: want speech %q, got: %q", c.turn(), text, got)
+	}
+	return c
+}
+
+func (c *Conversation) ExpectSpeechContains(text string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	if got := c.Speech(); !strings.Contains(got, text) {
+		c.t.Fatalf("%s: want speech containing %q, got: %q", c.turn(), text, got)
+	}
+	return c
+}
+
+// ExpectAsk checks the speech of a turn keeping the session open.
+func (c *Conversation) ExpectAsk(text string) *Conversation {
+	c.t.Helper()
+	c.ExpectSpeech(text)
+	if c.Ended() {
+		c.t.Fatalf("%s: want session kept open after %q", c.turn(), text)
+	}
+	return c
+}
+
+// ExpectTell checks the speech of a turn ending the session.
+func (c *Conversation) ExpectTell(text string) *Conversation {
+	c.t.Helper()
+	c.ExpectSpeech(text)
+	return c.ExpectEnd()
+}
+
+func (c *Conversation) ExpectEnd() *Conversation {
+	c.t.Helper()
+	if !c.Ended() {
+		c.t.Fatalf("%s: want session ended", c.turn())
+	}
+	return c
+}
+
+func (c *Conversation) ExpectDirective(typ sp.DirectiveType) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	if c.Directive(typ) == nil {
+		c.t.Fatalf("%s: want directive %s", c.turn(), typ)
+	}
+	return c
+}
+
+func (c *Conversation) ExpectNoDirective(typ sp.DirectiveType) *Conversation {
+	c.t.Helper()
+	if c.Directive(typ) != nil {
+		c.t.Fatalf("%s: want no directive %s, got: %+v", c.turn(), typ, c.Directive(typ))
+	}
+	return c
+}
+
+// ExpectParameter checks the string value of a context parameter returned.
+func (c *Conversation) ExpectParameter(k, v string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	var params sp.CtxParams
+	if c.response.Context != nil {
+		params = c.response.Context.CtxParams
+	}
+	if got, err := params.AsString(k); err != nil || got != v {
+		c.t.Fatalf("%s: want parameter %s = %q, got: %q (%v)", c.turn(), k, v, got, err)
+	}
+	return c
+}
+
+func (c *Conversation) ExpectNoParameter(k string) *Conversation {
+	c.t.Helper()
+	if c.response.Context != nil && c.response.Context.GetParameter(k) != nil {
+		c.t.Fatalf("%s: want no parameter %s, got: %+v", c.turn(), k,
+			c.response.Context.GetParameter(k))
+	}
+	return c
+}
+
+// ExpectSlotElicit checks that the turn elicited the slot in a dialog kept
+// open.
+func (c *Conversation) ExpectSlotElicit(slot string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	if c.Ended() || c.after.LastSlot != slot ||
+		c.after.GetAttempts(slot) <= c.before.GetAttempts(slot) {
+		c.t.Fatalf("%s: want %s elicited, got dialog: %+v", c.turn(), slot, c.after)
+	}
+	return c
+}
+
+// ExpectPrompt checks that the turn said the prompt of the dialog model.
+func (c *Conversation) ExpectPrompt(promptId string) *Conversation {
+	c.t.Helper()
+	c.expectOK()
+	if c.after.LastPromptId != promptId {
+		c.t.Fatalf("%s: want prompt %s, got: %s", c.turn(), promptId,
+			c.after.LastPromptId)
+	}
+	return c
+}
+
+// ExpectDialog checks the intent of the dialog in progress, none when empty.
+func (c *Conversation) ExpectDialog(intent string) *Conversation {
+	c.t.Helper()
+	if c.after.IntentName != intent {
+		c.t.Fatalf("%s: want dialog of %q, got: %q", c.turn(), intent, c.after.IntentName)
+	}
+	return c
+}
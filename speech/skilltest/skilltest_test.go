@@ -0,0 +1,93 @@
+package skilltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/directives"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	sp "roobo.com/rosai-skills-kit-sdk-for-go/speech/speechlet"
+)
+
+// tripSpeechlet plans a trip with the PlanMyTrip dialog of the test model.
+type tripSpeechlet struct{}
+
+func (ts *tripSpeechlet) OnSessionStarted(re *sp.RequestEnvelope) error {
+	return nil
+}
+
+func (ts *tripSpeechlet) OnLaunch(re *sp.RequestEnvelope) (*sp.Response, error) {
+	return sp.NewAskResponse("Where do you want to go?"), nil
+}
+
+func (ts *tripSpeechlet) OnIntent(re *sp.RequestEnvelope) (*sp.Response, *sp.Context, error) {
+	req := re.Request.(*sp.IntentRequest)
+	intent := req.Intent
+	if req.DialogState == slu.STARTED {
+		intent.SetSlot(slu.NewSlot("fromCity").WithStringValue("Beijing"))
+	}
+	if req.DialogState != slu.COMPLETED {
+		return sp.NewDelegateResponse([]directives.Directive{
+			directives.NewDelegateDirective(intent)}), nil, nil
+	}
+	speech := fmt.Sprintf("Trip to %s on %s planned", intent.GetSlot("toCity").GetStringValue(),
+		intent.GetSlot("travelDate").GetStringValue())
+	return sp.NewTellResponse(speech), nil, nil
+}
+
+func (ts *tripSpeechlet) OnSessionEnded(re *sp.RequestEnvelope) error {
+	return nil
+}
+
+func newTripHandler(t *testing.T) *sp.RequestHandler {
+	data, err := ioutil.ReadFile("../conf/dialog_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal(data, dm); err != nil {
+		t.Fatal(err)
+	}
+	return &sp.RequestHandler{Speechlet: &tripSpeechlet{}, DialogModel: dm}
+}
+
+func TestConversation(t *testing.T) {
+	New(t, newTripHandler(t)).
+		Launch().ExpectAsk("Where do you want to go?").
+		Say("PlanMyTrip", Slots{"travelDate": "2018-04-11"}).
+		ExpectSlotElicit("toCity").ExpectDialog("PlanMyTrip").
+		ExpectParameter("travelDate", "2018-04-11").
+		Say("PlanMyTrip", Slots{"toCity": "Seattle"}).
+		ExpectTell("Trip to Seattle on 2018-04-11 planned").
+		ExpectParameter("toCity", "Seattle").ExpectDialog("").
+		// a new dialog starts after the end
+		Say("PlanMyTrip", Slots{"toCity": "Paris"}).ExpectSlotElicit("travelDate")
+}
+
+func TestConversationReprompt(t *testing.T) {
+	c := New(t, newTripHandler(t))
+	c.Say("PlanMyTrip", Slots{"travelDate": "2018-04-11"}).ExpectSlotElicit("toCity").
+		Say("PlanMyTrip", nil).ExpectSlotElicit("toCity")
+	if n := c.Session().GetDialogStateMachine().GetAttempts("toCity"); n != 2 {
+		t.Fatalf("want toCity elicited twice, got: %d", n)
+	}
+	if c.Response().Reprompt == nil {
+		t.Fatal("want reprompt of toCity")
+	}
+}
+
+func TestRunScripts(t *testing.T) {
+	RunScripts(t, newTripHandler(t), "testdata/*.yaml")
+	RunScripts(t, newTripHandler(t), "testdata/*.json")
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("a\nb\nc", "a\nB\nc\nd")
+	want := "-   2 b\n+   2 B\n+   4 d\n"
+	if got != want {
+		t.Fatalf("want %q, got: %q", want, got)
+	}
+}
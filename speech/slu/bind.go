@@ -0,0 +1,246 @@
+package slu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Struct tags read by Bind.
+const (
+	TagSlot     = "slot"
+	TagCtx      = "ctx"
+	TagDefault  = "default"
+	TagRequired = "required"
+)
+
+var ErrBindTarget = errors.New("bind target must be a non-nil pointer to struct")
+
+// ParamGetter gives access to context parameters. It is implemented by
+// speechlet.CtxParams and *speechlet.Context.
+type ParamGetter interface {
+	GetParameter(k string) *Value
+}
+
+// MissingField is a required field Bind could not fill.
+type MissingField struct {
+	Field string `json:"field"`
+	Slot  string `json:"slot,omitempty"`
+}
+
+// MissingError is returned by Bind when required fields are left empty. The
+// slots of the missing fields can be elicited in order.
+type MissingError struct {
+	Intent  string          `json:"intent"`
+	Missing []*MissingField `json:"missing"`
+}
+
+func (e *MissingError) Error() string {
+	names := make([]string, 0, len(e.Missing))
+	for _, v := range e.Missing {
+		names = append(names, v.Field)
+	}
+	return fmt.Sprintf("intent %s missing required fields: %s", e.Intent,
+		strings.Join(names, ", "))
+}
+
+// SlotToElicit returns the slot of the first missing field which has one.
+func (e *MissingError) SlotToElicit() string {
+	for _, v := range e.Missing {
+		if v.Slot != "" {
+			return v.Slot
+		}
+	}
+	return ""
+}
+
+// Bind fills the fields of the struct pointed to by v from the slots of the
+// intent, then the context parameters, then the defaults, as told by the
+// field tags:
+//
+//	type Query struct {
+//		City string `slot:"city" ctx:"city" default:"北京"`
+//		Date string `slot:"date" required:"true"`
+//	}
+//
+// Supported field types are string, bool, integers, unsigned integers,
+// floats, []string, []interface{}, map[string]interface{},
+// []map[string]interface{} and *Value. Values are converted to the field type
+// like Value.AsInt and friends do, the numbers the field can not hold are
+// errors. A *MissingError is returned if a required field is still empty.
+func Bind(intent *Intent, ctx ParamGetter, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTarget
+	}
+	if ctx != nil {
+		cv := reflect.ValueOf(ctx)
+		if (cv.Kind() == reflect.Ptr || cv.Kind() == reflect.Map) && cv.IsNil() {
+			ctx = nil
+		}
+	}
+	missing := &MissingError{}
+	if intent != nil {
+		missing.Intent = intent.Name
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		slotName, ctxName := sf.Tag.Get(TagSlot), sf.Tag.Get(TagCtx)
+		def, hasDef := sf.Tag.Lookup(TagDefault)
+		if slotName == "" && ctxName == "" && !hasDef {
+			continue
+		}
+		fv := rv.Field(i)
+		bound := false
+		if slotName != "" {
+			if slot := intent.GetSlot(slotName); slot.HasValue() {
+				if err := setField(fv, slot.GetValue()); err != nil {
+					return errors.New(fmt.Sprintf("bind slot %s to field %s: %s",
+						slotName, sf.Name, err))
+				}
+				bound = true
+			}
+		}
+		if !bound && ctxName != "" && ctx != nil {
+			if val := ctx.GetParameter(ctxName); val.HasValue() {
+				if err := setField(fv, val); err != nil {
+					return errors.New(fmt.Sprintf("bind context %s to field %s: %s",
+						ctxName, sf.Name, err))
+				}
+				bound = true
+			}
+		}
+		if !bound && hasDef {
+			if err := setFieldString(fv, def); err != nil {
+				return errors.New(fmt.Sprintf("bind default %q to field %s: %s",
+					def, sf.Name, err))
+			}
+			bound = true
+		}
+		if !bound && sf.Tag.Get(TagRequired) == "true" {
+			missing.Missing = append(missing.Missing,
+				&MissingField{Field: sf.Name, Slot: slotName})
+		}
+	}
+	if len(missing.Missing) > 0 {
+		return missing
+	}
+	return nil
+}
+
+var valueType = reflect.TypeOf(&Value{})
+
+func setField(fv reflect.Value, val *Value) error {
+	if fv.Type() == valueType {
+		fv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	var (
+		x   interface{}
+		err error
+	)
+	switch fv.Kind() {
+	default:
+		return errors.New(fmt.Sprintf("unsupported field type %s", fv.Type()))
+	case reflect.String:
+		x, err = val.AsString()
+	case reflect.Bool:
+		x, err = val.AsBool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int
+		if i, err = val.AsInt(); err == nil {
+			if fv.OverflowInt(int64(i)) {
+				return errors.New(fmt.Sprintf("%d overflows %s", i, fv.Type()))
+			}
+			fv.SetInt(int64(i))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i int
+		if i, err = val.AsInt(); err == nil {
+			if i < 0 || fv.OverflowUint(uint64(i)) {
+				return errors.New(fmt.Sprintf("%d overflows %s", i, fv.Type()))
+			}
+			fv.SetUint(uint64(i))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = val.AsFloat(); err == nil {
+			if fv.OverflowFloat(f) {
+				return errors.New(fmt.Sprintf("%g overflows %s", f, fv.Type()))
+			}
+			fv.SetFloat(f)
+			return nil
+		}
+	case reflect.Slice:
+		switch fv.Type().Elem().Kind() {
+		case reflect.String:
+			x, err = val.AsStrArray()
+		case reflect.Interface:
+			x, err = val.AsArray()
+		case reflect.Map:
+			x, err = val.AsMapArray()
+		default:
+			return errors.New(fmt.Sprintf("unsupported field type %s", fv.Type()))
+		}
+	case reflect.Map:
+		x, err = val.AsMap()
+	}
+	if err != nil {
+		return err
+	}
+	xv := reflect.ValueOf(x)
+	if !xv.Type().ConvertibleTo(fv.Type()) {
+		return errors.New(fmt.Sprintf("%s not convertible to %s", xv.Type(), fv.Type()))
+	}
+	fv.Set(xv.Convert(fv.Type()))
+	return nil
+}
+
+func setFieldString(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	default:
+		return errors.New(fmt.Sprintf("unsupported default for field type %s", fv.Type()))
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return errors.New(fmt.Sprintf("unsupported default for field type %s",
+				fv.Type()))
+		}
+		fv.Set(reflect.ValueOf(strings.Split(s, ",")).Convert(fv.Type()))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package slu
+
+import (
+	"testing"
+)
+
+type testCtx map[string]*Value
+
+func (c testCtx) GetParameter(k string) *Value {
+	return c[k]
+}
+
+type tripQuery struct {
+	FromCity string   `slot:"fromCity" ctx:"city" default:"Beijing"`
+	ToCity   string   `slot:"toCity" ctx:"toCity" required:"true"`
+	Date     string   `slot:"travelDate" required:"true"`
+	Days     int      `slot:"days" default:"3"`
+	Budget   float64  `ctx:"budget"`
+	Friends  []string `slot:"friends"`
+	Raw      *Value   `slot:"toCity"`
+	ignored  string   `slot:"fromCity"`
+	Comment  string
+}
+
+func TestBind(t *testing.T) {
+	intent := NewIntent("PlanMyTrip").
+		WithSlot(NewSlot("toCity").WithStringValue("Sanya")).
+		WithSlot(NewSlot("travelDate").WithStringValue("2018-04-05")).
+		WithSlot(NewSlot("friends").WithStrArrayValue([]string{"Tom", "Jerry"}))
+	ctx := testCtx{"city": NewStringValue("Shanghai"), "budget": NewFloatValue(99.5)}
+	var q tripQuery
+	if err := Bind(intent, ctx, &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.FromCity != "Shanghai" || q.ToCity != "Sanya" || q.Date != "2018-04-05" ||
+		q.Days != 3 || q.Budget != 99.5 || len(q.Friends) != 2 ||
+		q.Raw.GetType() != StringType || q.ignored != "" {
+		t.Fatalf("bind got: %+v", q)
+	}
+	// defaults apply without context
+	q = tripQuery{}
+	if err := Bind(intent, nil, &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.FromCity != "Beijing" {
+		t.Fatalf("fromCity want default Beijing, got: %s", q.FromCity)
+	}
+}
+
+func TestBindMissing(t *testing.T) {
+	intent := NewIntent("PlanMyTrip").WithSlot(NewSlot("toCity"))
+	var q tripQuery
+	err := Bind(intent, testCtx(nil), &q)
+	missing, ok := err.(*MissingError)
+	if !ok {
+		t.Fatalf("want MissingError, got: %v", err)
+	}
+	if len(missing.Missing) != 2 || missing.SlotToElicit() != "toCity" ||
+		missing.Missing[1].Slot != "travelDate" {
+		t.Fatalf("want toCity and travelDate missing, got: %s", missing)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	var q tripQuery
+	if err := Bind(nil, nil, q); err != ErrBindTarget {
+		t.Fatalf("want ErrBindTarget, got: %v", err)
+	}
+	intent := NewIntent("PlanMyTrip").WithSlot(NewSlot("days").WithStringValue("many"))
+	if err := Bind(intent, nil, &q); err == nil {
+		t.Fatal("bind string slot to int field should fail")
+	}
+	intent.SetSlot(NewSlot("days").WithStringValue("5"))
+	if err := Bind(intent, nil, &q); err != nil {
+		if _, ok := err.(*MissingError); !ok {
+			t.Fatal(err)
+		}
+	}
+	if q.Days != 5 {
+		t.Fatalf("want 5 days, got: %d", q.Days)
+	}
+}
+
+func TestBindOverflow(t *testing.T) {
+	var q struct {
+		Small  int8    `slot:"small"`
+		Count  uint16  `slot:"count" default:"7"`
+		Weight float32 `slot:"weight"`
+	}
+	for _, c := range []struct {
+		slot  string
+		value *Value
+	}{
+		{"small", NewIntValue(300)},
+		{"count", NewIntValue(-1)},
+		{"count", NewIntValue(70000)},
+		{"weight", NewFloatValue(1e300)},
+	} {
+		intent := NewIntent("Order").WithSlot(NewSlot(c.slot).WithValue(c.value))
+		if err := Bind(intent, nil, &q); err == nil {
+			t.Fatalf("bind %s %+v want overflow error, got: %+v", c.slot, c.value, q)
+		}
+	}
+	intent := NewIntent("Order").WithSlot(NewSlot("small").WithValue(NewIntValue(-128)))
+	if err := Bind(intent, nil, &q); err != nil || q.Small != -128 || q.Count != 7 {
+		t.Fatalf("want -128 and the default 7, got: %+v, %v", q, err)
+	}
+}
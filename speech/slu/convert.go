@@ -0,0 +1,456 @@
+package slu
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The As* accessors below convert between compatible value types instead of
+// requiring ValueType to match like the Get*Value getters do: strings and
+// numbers are converted to each other, numbers and bools as 1 and 0, and JSON
+// strings to maps and arrays. They return NilErr for a nil or empty value,
+// TypeErr when no conversion applies and ValueErr when it fails. The Must*
+// variants panic on error and the *OrDefault ones return def instead.
+
+// AsInt parses integers as such, it returns ValueErr for a number with a
+// fractional part rather than truncating it.
+func (v *Value) AsInt() (int, error) {
+	if !v.HasValue() {
+		return 0, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case int:
+		return vv, nil
+	case int32:
+		return int(vv), nil
+	case int64:
+		return int(vv), nil
+	case json.Number:
+		if i, err := vv.Int64(); err == nil {
+			return int(i), nil
+		}
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(vv), 10, 64); err == nil {
+			return int(i), nil
+		}
+	}
+	f, err := v.asNumber()
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, ValueErr
+	}
+	return int(f), nil
+}
+
+func (v *Value) MustInt() int {
+	i, err := v.AsInt()
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+func (v *Value) IntOrDefault(def int) int {
+	if i, err := v.AsInt(); err == nil {
+		return i
+	}
+	return def
+}
+
+func (v *Value) AsFloat() (float64, error) {
+	return v.asNumber()
+}
+
+func (v *Value) MustFloat() float64 {
+	f, err := v.AsFloat()
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (v *Value) FloatOrDefault(def float64) float64 {
+	if f, err := v.AsFloat(); err == nil {
+		return f
+	}
+	return def
+}
+
+func (v *Value) AsBool() (bool, error) {
+	if !v.HasValue() {
+		return false, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case bool:
+		return vv, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(vv))
+		if err != nil {
+			return false, ValueErr
+		}
+		return b, nil
+	}
+	f, err := v.asNumber()
+	if err != nil {
+		return false, err
+	}
+	return f != 0, nil
+}
+
+func (v *Value) MustBool() bool {
+	b, err := v.AsBool()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (v *Value) BoolOrDefault(def bool) bool {
+	if b, err := v.AsBool(); err == nil {
+		return b
+	}
+	return def
+}
+
+// AsString formats numbers and bools, and encodes arrays and maps to JSON.
+func (v *Value) AsString() (string, error) {
+	if !v.HasValue() {
+		return "", NilErr
+	}
+	switch vv := v.Value.(type) {
+	case string:
+		return vv, nil
+	case bool:
+		return strconv.FormatBool(vv), nil
+	case int:
+		return strconv.Itoa(vv), nil
+	case int64:
+		return strconv.FormatInt(vv, 10), nil
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64), nil
+	case json.Number:
+		return vv.String(), nil
+	}
+	raw, err := json.Marshal(v.Value)
+	if err != nil {
+		return "", ValueErr
+	}
+	return string(raw), nil
+}
+
+func (v *Value) MustString() string {
+	s, err := v.AsString()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (v *Value) StringOrDefault(def string) string {
+	if s, err := v.AsString(); err == nil {
+		return s
+	}
+	return def
+}
+
+// AsArray accepts any array value or a JSON array string.
+func (v *Value) AsArray() ([]interface{}, error) {
+	if !v.HasValue() {
+		return nil, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case []interface{}:
+		return vv, nil
+	case []string:
+		a := make([]interface{}, 0, len(vv))
+		for _, s := range vv {
+			a = append(a, s)
+		}
+		return a, nil
+	case []map[string]interface{}:
+		a := make([]interface{}, 0, len(vv))
+		for _, m := range vv {
+			a = append(a, m)
+		}
+		return a, nil
+	case string:
+		var a []interface{}
+		if err := json.Unmarshal([]byte(vv), &a); err != nil {
+			return nil, ValueErr
+		}
+		return a, nil
+	}
+	return nil, TypeErr
+}
+
+func (v *Value) MustArray() []interface{} {
+	a, err := v.AsArray()
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (v *Value) ArrayOrDefault(def []interface{}) []interface{} {
+	if a, err := v.AsArray(); err == nil {
+		return a
+	}
+	return def
+}
+
+// AsStrArray converts the elements of an array to strings. A scalar value
+// gives an array of one element.
+func (v *Value) AsStrArray() ([]string, error) {
+	if !v.HasValue() {
+		return nil, NilErr
+	}
+	if ss, ok := v.Value.([]string); ok {
+		return ss, nil
+	}
+	a, err := v.AsArray()
+	if err == nil {
+		return Conv2StrSlice(a), nil
+	}
+	if s, ok := v.Value.(string); ok {
+		return []string{s}, nil
+	}
+	if err != TypeErr {
+		return nil, err
+	}
+	s, err := v.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func (v *Value) MustStrArray() []string {
+	ss, err := v.AsStrArray()
+	if err != nil {
+		panic(err)
+	}
+	return ss
+}
+
+func (v *Value) StrArrayOrDefault(def []string) []string {
+	if ss, err := v.AsStrArray(); err == nil {
+		return ss
+	}
+	return def
+}
+
+// AsMap accepts a map value or a JSON object string.
+func (v *Value) AsMap() (map[string]interface{}, error) {
+	if !v.HasValue() {
+		return nil, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case map[string]interface{}:
+		return vv, nil
+	case string:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(vv), &m); err != nil {
+			return nil, ValueErr
+		}
+		return m, nil
+	}
+	return nil, TypeErr
+}
+
+func (v *Value) MustMap() map[string]interface{} {
+	m, err := v.AsMap()
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (v *Value) MapOrDefault(def map[string]interface{}) map[string]interface{} {
+	if m, err := v.AsMap(); err == nil {
+		return m
+	}
+	return def
+}
+
+// AsMapArray accepts an array of maps, a JSON array string or a single map.
+func (v *Value) AsMapArray() ([]map[string]interface{}, error) {
+	if !v.HasValue() {
+		return nil, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case []map[string]interface{}:
+		return vv, nil
+	case map[string]interface{}:
+		return []map[string]interface{}{vv}, nil
+	case string:
+		var ma []map[string]interface{}
+		if err := json.Unmarshal([]byte(vv), &ma); err != nil {
+			return nil, ValueErr
+		}
+		return ma, nil
+	case []interface{}:
+		ma := make([]map[string]interface{}, 0, len(vv))
+		for _, e := range vv {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, ValueErr
+			}
+			ma = append(ma, m)
+		}
+		return ma, nil
+	}
+	return nil, TypeErr
+}
+
+func (v *Value) MustMapArray() []map[string]interface{} {
+	ma, err := v.AsMapArray()
+	if err != nil {
+		panic(err)
+	}
+	return ma
+}
+
+func (v *Value) MapArrayOrDefault(def []map[string]interface{}) []map[string]interface{} {
+	if ma, err := v.AsMapArray(); err == nil {
+		return ma
+	}
+	return def
+}
+
+func (v *Value) asNumber() (float64, error) {
+	if !v.HasValue() {
+		return 0, NilErr
+	}
+	switch vv := v.Value.(type) {
+	case int:
+		return float64(vv), nil
+	case int32:
+		return float64(vv), nil
+	case int64:
+		return float64(vv), nil
+	case float32:
+		return float64(vv), nil
+	case float64:
+		return vv, nil
+	case json.Number:
+		f, err := vv.Float64()
+		if err != nil {
+			return 0, ValueErr
+		}
+		return f, nil
+	case bool:
+		if vv {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(vv), 64)
+		if err != nil {
+			return 0, ValueErr
+		}
+		return f, nil
+	}
+	return 0, TypeErr
+}
+
+// Slot accessors, see the Value ones.
+
+func (slot *Slot) AsInt() (int, error) {
+	return slot.GetValue().AsInt()
+}
+
+func (slot *Slot) MustInt() int {
+	return slot.GetValue().MustInt()
+}
+
+func (slot *Slot) IntOrDefault(def int) int {
+	return slot.GetValue().IntOrDefault(def)
+}
+
+func (slot *Slot) AsFloat() (float64, error) {
+	return slot.GetValue().AsFloat()
+}
+
+func (slot *Slot) MustFloat() float64 {
+	return slot.GetValue().MustFloat()
+}
+
+func (slot *Slot) FloatOrDefault(def float64) float64 {
+	return slot.GetValue().FloatOrDefault(def)
+}
+
+func (slot *Slot) AsBool() (bool, error) {
+	return slot.GetValue().AsBool()
+}
+
+func (slot *Slot) MustBool() bool {
+	return slot.GetValue().MustBool()
+}
+
+func (slot *Slot) BoolOrDefault(def bool) bool {
+	return slot.GetValue().BoolOrDefault(def)
+}
+
+func (slot *Slot) AsString() (string, error) {
+	return slot.GetValue().AsString()
+}
+
+func (slot *Slot) MustString() string {
+	return slot.GetValue().MustString()
+}
+
+func (slot *Slot) StringOrDefault(def string) string {
+	return slot.GetValue().StringOrDefault(def)
+}
+
+func (slot *Slot) AsArray() ([]interface{}, error) {
+	return slot.GetValue().AsArray()
+}
+
+func (slot *Slot) MustArray() []interface{} {
+	return slot.GetValue().MustArray()
+}
+
+func (slot *Slot) ArrayOrDefault(def []interface{}) []interface{} {
+	return slot.GetValue().ArrayOrDefault(def)
+}
+
+func (slot *Slot) AsStrArray() ([]string, error) {
+	return slot.GetValue().AsStrArray()
+}
+
+func (slot *Slot) MustStrArray() []string {
+	return slot.GetValue().MustStrArray()
+}
+
+func (slot *Slot) StrArrayOrDefault(def []string) []string {
+	return slot.GetValue().StrArrayOrDefault(def)
+}
+
+func (slot *Slot) AsMap() (map[string]interface{}, error) {
+	return slot.GetValue().AsMap()
+}
+
+func (slot *Slot) MustMap() map[string]interface{} {
+	return slot.GetValue().MustMap()
+}
+
+func (slot *Slot) MapOrDefault(def map[string]interface{}) map[string]interface{} {
+	return slot.GetValue().MapOrDefault(def)
+}
+
+func (slot *Slot) AsMapArray() ([]map[string]interface{}, error) {
+	return slot.GetValue().AsMapArray()
+}
+
+func (slot *Slot) MustMapArray() []map[string]interface{} {
+	return slot.GetValue().MustMapArray()
+}
+
+func (slot *Slot) MapArrayOrDefault(def []map[string]interface{}) []map[string]interface{} {
+	return slot.GetValue().MapArrayOrDefault(def)
+}
@@ -0,0 +1,96 @@
+package slu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValueCoercion(t *testing.T) {
+	if i, err := NewStringValue(" 3 ").AsInt(); err != nil || i != 3 {
+		t.Fatalf("string to int, got: %d, %v", i, err)
+	}
+	if f, err := NewIntValue(2).AsFloat(); err != nil || f != 2.0 {
+		t.Fatalf("int to float, got: %f, %v", f, err)
+	}
+	if s, err := NewFloatValue(1.5).AsString(); err != nil || s != "1.5" {
+		t.Fatalf("float to string, got: %s, %v", s, err)
+	}
+	if b, err := NewIntValue(0).AsBool(); err != nil || b {
+		t.Fatalf("int to bool, got: %t, %v", b, err)
+	}
+	if i, err := NewBoolValue(true).AsInt(); err != nil || i != 1 {
+		t.Fatalf("bool to int, got: %d, %v", i, err)
+	}
+	if b, err := NewStringValue("true").AsBool(); err != nil || !b {
+		t.Fatalf("string to bool, got: %t, %v", b, err)
+	}
+	m, err := NewStringValue(`{"city":"北京"}`).AsMap()
+	if err != nil || m["city"] != "北京" {
+		t.Fatalf("JSON string to map, got: %v, %v", m, err)
+	}
+	if s, err := NewMapValue(m).AsString(); err != nil || s != `{"city":"北京"}` {
+		t.Fatalf("map to string, got: %s, %v", s, err)
+	}
+	ma, err := NewArrayValue([]interface{}{m}).AsMapArray()
+	if err != nil || len(ma) != 1 || ma[0]["city"] != "北京" {
+		t.Fatalf("array to map array, got: %v, %v", ma, err)
+	}
+	if ss, err := NewArrayValue([]interface{}{1, "a"}).AsStrArray(); err != nil ||
+		len(ss) != 2 || ss[0] != "1" {
+		t.Fatalf("array to string array, got: %v, %v", ss, err)
+	}
+}
+
+func TestValueAsIntPrecision(t *testing.T) {
+	const big = 1<<53 + 1
+	for _, v := range []*Value{NewStringValue("9007199254740993"),
+		NewValue(IntType, json.Number("9007199254740993")), NewValue(IntType, int64(big))} {
+		if i, err := v.AsInt(); err != nil || i != big {
+			t.Fatalf("%v: want %d, got: %d, %v", v.Value, big, i, err)
+		}
+	}
+	if i, err := NewFloatValue(3).AsInt(); err != nil || i != 3 {
+		t.Fatalf("want 3 from an integral float, got: %d, %v", i, err)
+	}
+	if i, err := NewStringValue("-4.0").AsInt(); err != nil || i != -4 {
+		t.Fatalf("want -4, got: %d, %v", i, err)
+	}
+}
+
+func TestValueCoercionErrors(t *testing.T) {
+	if _, err := NewStringValue("abc").AsInt(); err != ValueErr {
+		t.Fatalf("want ValueErr, got: %v", err)
+	}
+	if _, err := NewMapValue(map[string]interface{}{}).AsInt(); err != TypeErr {
+		t.Fatalf("want TypeErr, got: %v", err)
+	}
+	for _, v := range []*Value{NewStringValue("3.7"), NewFloatValue(3.7),
+		NewValue(StringType, json.Number("3.7")), NewStringValue("1e300")} {
+		if i, err := v.AsInt(); err != ValueErr {
+			t.Fatalf("%v: want ValueErr for a non-integral number, got: %d, %v", v.Value, i, err)
+		}
+	}
+	var v *Value
+	if _, err := v.AsString(); err != NilErr {
+		t.Fatalf("want NilErr, got: %v", err)
+	}
+	if _, err := v.GetMapArrayValue(); err != NilErr {
+		t.Fatalf("want NilErr, got: %v", err)
+	}
+	if v.GetValue() != nil || v.GetOrigin() != nil || v.GetLogic() != "" {
+		t.Fatal("nil value getters should return zero values")
+	}
+	if v.IntOrDefault(7) != 7 || NewStringValue("x").FloatOrDefault(1.5) != 1.5 {
+		t.Fatal("OrDefault should return the default on error")
+	}
+	var slot *Slot
+	if slot.StringOrDefault("def") != "def" {
+		t.Fatal("nil slot should return the default")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustInt should panic on error")
+		}
+	}()
+	slot.MustInt()
+}
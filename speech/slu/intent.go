@@ -149,6 +149,9 @@ func (v *Value) WithOrigin(o interface{}) *Value {
 }
 
 func (v *Value) GetOrigin() interface{} {
+	if v == nil {
+		return nil
+	}
 	return v.Origin
 }
 
@@ -162,6 +165,9 @@ func (v *Value) WithLogic(l string) *Value {
 }
 
 func (v *Value) GetLogic() string {
+	if v == nil {
+		return ""
+	}
 	return v.Logic
 }
 
@@ -175,6 +181,9 @@ func (v *Value) WithPriority(p interface{}) *Value {
 }
 
 func (v *Value) GetPriority() interface{} {
+	if v == nil {
+		return nil
+	}
 	return v.Priority
 }
 
@@ -188,6 +197,9 @@ func (v *Value) WithTag(t interface{}) *Value {
 }
 
 func (v *Value) GetTag() interface{} {
+	if v == nil {
+		return nil
+	}
 	return v.Tag
 }
 
@@ -223,10 +235,16 @@ func (v *Value) HasValue() bool {
 }
 
 func (v *Value) GetValue() interface{} {
+	if v == nil {
+		return nil
+	}
 	return v.Value
 }
 
 func (v *Value) GetIntValue() (int, error) {
+	if v == nil {
+		return -1, NilErr
+	}
 	switch v.GetType() {
 	default:
 		return -1, TypeErr
@@ -242,6 +260,9 @@ func (v *Value) GetIntValue() (int, error) {
 }
 
 func (v *Value) GetBoolValue() (bool, error) {
+	if v == nil {
+		return false, NilErr
+	}
 	switch v.GetType() {
 	default:
 		return false, TypeErr
@@ -255,6 +276,9 @@ func (v *Value) GetBoolValue() (bool, error) {
 }
 
 func (v *Value) GetFloatValue() (float64, error) {
+	if v == nil {
+		return 0.0, NilErr
+	}
 	switch v.GetType() {
 	default:
 		return 0.0, TypeErr
@@ -268,6 +292,9 @@ func (v *Value) GetFloatValue() (float64, error) {
 }
 
 func (v *Value) GetStringValue() (string, error) {
+	if v == nil {
+		return "", NilErr
+	}
 	switch v.GetType() {
 	default:
 		return "", TypeErr
@@ -280,20 +307,21 @@ func (v *Value) GetStringValue() (string, error) {
 	}
 }
 
+// GetStringOrgin returns the origin text of the value, whatever its type.
 func (v *Value) GetStringOrgin() (string, error) {
-	switch v.GetType() {
-	default:
-		return "", TypeErr
-	case StringType:
-		if vv, ok := v.Origin.(string); ok {
-			return vv, nil
-		} else {
-			return "", ValueErr
-		}
+	if v == nil {
+		return "", NilErr
+	}
+	if vv, ok := v.Origin.(string); ok {
+		return vv, nil
 	}
+	return "", ValueErr
 }
 
 func (v *Value) GetArrayValue() ([]interface{}, error) {
+	if v == nil {
+		return nil, NilErr
+	}
 	switch v.GetType() {
 	default:
 		return nil, TypeErr
@@ -307,6 +335,9 @@ func (v *Value) GetArrayValue() ([]interface{}, error) {
 }
 
 func (v *Value) GetStrArrayValue() ([]string, error) {
+	if v == nil {
+		return nil, NilErr
+	}
 	switch v.GetType() {
 	default:
 		return nil, TypeErr
@@ -341,6 +372,9 @@ func (v *Value) GetMapValue() (map[string]interface{}, error) {
 }
 
 func (v *Value) GetMapArrayValue() ([]map[string]interface{}, error) {
+	if v == nil {
+		return nil, NilErr
+	}
 	if v.GetType() == MapArrayType {
 		if vv, ok := v.Value.([]map[string]interface{}); ok {
 			return vv, nil
@@ -361,6 +395,9 @@ type Slot struct {
 	Value              *Value                        `json:"value,omitempty"`
 	ConfirmationStatus ConfirmationStatus            `json:"confirmationStatus"`
 	Resolutions        *entityresolution.Resolutions `json:"resolutions,omitempty"`
+	// Inherited is set when the value was carried over from another intent
+	// instead of being said by the user.
+	Inherited bool `json:"inherited,omitempty"`
 }
 
 func (slot *Slot) CanConfirm() bool {
@@ -439,6 +476,18 @@ func (slot *Slot) WithMapArrayValue(ma []map[string]interface{}) *Slot {
 	return slot
 }
 
+func (slot *Slot) WithInherited(b bool) *Slot {
+	slot.Inherited = b
+	return slot
+}
+
+func (slot *Slot) IsInherited() bool {
+	if slot == nil {
+		return false
+	}
+	return slot.Inherited
+}
+
 func (slot *Slot) WithStatus(sta ConfirmationStatus) *Slot {
 	if slot == nil {
 		return nil
@@ -660,6 +709,24 @@ func (intent *Intent) Merge(obj *Intent) bool {
 	return true
 }
 
+// Clone returns a copy of the intent whose slots can be modified without
+// touching the original ones. Slot values are shared.
+func (intent *Intent) Clone() *Intent {
+	if intent == nil {
+		return nil
+	}
+	c := NewIntent(intent.Name).WithSubName(intent.SubName).
+		WithStatus(intent.ConfirmationStatus)
+	for k, v := range intent.Slots {
+		if v == nil {
+			continue
+		}
+		slot := *v
+		c.Slots[k] = &slot
+	}
+	return c
+}
+
 func (intent *Intent) CleanSlots(mi *model.Intent) {
 	for k, v := range intent.Slots {
 		if !v.HasValue() && !mi.GetSlot(v.Name).NeedElicit() {
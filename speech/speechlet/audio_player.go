@@ -0,0 +1,156 @@
+package speechlet
+
+import (
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/interfaces/system"
+)
+
+const (
+	AudioPlayerPlayDirectiveType       DirectiveType = "AudioPlayer.Play"
+	AudioPlayerStopDirectiveType       DirectiveType = "AudioPlayer.Stop"
+	AudioPlayerClearQueueDirectiveType DirectiveType = "AudioPlayer.ClearQueue"
+)
+
+const (
+	PlaybackStartedRequestType        RequestType = "AudioPlayer.PlaybackStarted"
+	PlaybackNearlyFinishedRequestType RequestType = "AudioPlayer.PlaybackNearlyFinished"
+	PlaybackFinishedRequestType       RequestType = "AudioPlayer.PlaybackFinished"
+	PlaybackFailedRequestType         RequestType = "AudioPlayer.PlaybackFailed"
+)
+
+// PlayBehavior tells how a Play directive affects the playback queue:
+// REPLACE_ALL stops the current stream and replaces the whole queue,
+// ENQUEUE adds the stream to the end of the queue, and REPLACE_ENQUEUED
+// replaces the queue but lets the current stream finish.
+type PlayBehavior string
+
+const (
+	REPLACE_ALL      PlayBehavior = "REPLACE_ALL"
+	ENQUEUE          PlayBehavior = "ENQUEUE"
+	REPLACE_ENQUEUED PlayBehavior = "REPLACE_ENQUEUED"
+)
+
+// ClearBehavior tells whether a ClearQueue directive also stops the current
+// stream (CLEAR_ALL) or only clears the streams enqueued (CLEAR_ENQUEUED).
+type ClearBehavior string
+
+const (
+	CLEAR_ENQUEUED ClearBehavior = "CLEAR_ENQUEUED"
+	CLEAR_ALL      ClearBehavior = "CLEAR_ALL"
+)
+
+func init() {
+	RegisterDirectiveType(AudioPlayerPlayDirectiveType,
+		func() Directive { return &PlayDirective{} })
+	RegisterDirectiveType(AudioPlayerStopDirectiveType,
+		func() Directive { return &StopDirective{} })
+	RegisterDirectiveType(AudioPlayerClearQueueDirectiveType,
+		func() Directive { return &ClearQueueDirective{} })
+}
+
+// AudioStream is a stream to play, identified by its token in the playback
+// requests sent back by the device.
+type AudioStream struct {
+	Token                 string `json:"token"`
+	URL                   string `json:"url"`
+	OffsetInMs            int64  `json:"offsetInMs"`
+	ExpectedPreviousToken string `json:"expectedPreviousToken,omitempty"`
+}
+
+type AudioItem struct {
+	Stream *AudioStream `json:"stream"`
+}
+
+type PlayDirective struct {
+	Type         DirectiveType `json:"type"`
+	PlayBehavior PlayBehavior  `json:"playBehavior"`
+	AudioItem    *AudioItem    `json:"audioItem"`
+}
+
+func NewPlayDirective(behavior PlayBehavior, token, url string,
+	offsetInMs int64) *PlayDirective {
+	return &PlayDirective{
+		Type:         AudioPlayerPlayDirectiveType,
+		PlayBehavior: behavior,
+		AudioItem: &AudioItem{Stream: &AudioStream{
+			Token:      token,
+			URL:        url,
+			OffsetInMs: offsetInMs,
+		}},
+	}
+}
+
+// WithExpectedPreviousToken makes an ENQUEUE directive apply only if the
+// stream playing is the one with the token.
+func (pd *PlayDirective) WithExpectedPreviousToken(token string) *PlayDirective {
+	pd.AudioItem.Stream.ExpectedPreviousToken = token
+	return pd
+}
+
+func (pd *PlayDirective) GetType() DirectiveType {
+	return pd.Type
+}
+
+func (pd *PlayDirective) GetStream() *AudioStream {
+	if pd.AudioItem == nil {
+		return nil
+	}
+	return pd.AudioItem.Stream
+}
+
+type StopDirective struct {
+	Type DirectiveType `json:"type"`
+}
+
+func NewStopDirective() *StopDirective {
+	return &StopDirective{Type: AudioPlayerStopDirectiveType}
+}
+
+func (sd *StopDirective) GetType() DirectiveType {
+	return sd.Type
+}
+
+type ClearQueueDirective struct {
+	Type          DirectiveType `json:"type"`
+	ClearBehavior ClearBehavior `json:"clearBehavior"`
+}
+
+func NewClearQueueDirective(behavior ClearBehavior) *ClearQueueDirective {
+	return &ClearQueueDirective{
+		Type:          AudioPlayerClearQueueDirectiveType,
+		ClearBehavior: behavior,
+	}
+}
+
+func (cd *ClearQueueDirective) GetType() DirectiveType {
+	return cd.Type
+}
+
+// AudioPlayerRequest is sent by the device when the playback state of a
+// stream changes. Err is only set for PlaybackFailed.
+type AudioPlayerRequest struct {
+	CoreRequest
+	Token      string        `json:"token"`
+	OffsetInMs int64         `json:"offsetInMs"`
+	Err        *system.Error `json:"error,omitempty"`
+}
+
+func NewAudioPlayerRequest(typ RequestType, reqId, ts, token string,
+	offsetInMs int64) *AudioPlayerRequest {
+	apr := new(AudioPlayerRequest)
+	apr.speechletRequest = new(speechletRequest)
+	apr.setBaseInfo(typ, reqId, ts)
+	apr.Token = token
+	apr.OffsetInMs = offsetInMs
+	return apr
+}
+
+// AudioPlayerSpeechlet is implemented by the Speechlets which play streams
+// with the AudioPlayer directives and want to follow their playback. The
+// responses may only carry AudioPlayer directives, e.g. the next stream to
+// enqueue on PlaybackNearlyFinished.
+type AudioPlayerSpeechlet interface {
+	OnPlaybackStarted(requestEnvelope *RequestEnvelope) (*Response, error)
+	OnPlaybackNearlyFinished(requestEnvelope *RequestEnvelope) (*Response, error)
+	OnPlaybackFinished(requestEnvelope *RequestEnvelope) (*Response, error)
+	OnPlaybackFailed(requestEnvelope *RequestEnvelope) (*Response, error)
+}
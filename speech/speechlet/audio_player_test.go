@@ -0,0 +1,125 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const playbackFailedReq = `{
+  "version": "2.0",
+  "context": {
+    "system": {
+      "user": {"userId": "rosai.user.test001", "appId": "rosai.app.test001"},
+      "device": {"deviceId": "rosai.device.test001"},
+      "skill": {"skillId": "rosai.skill.test001"}
+    }
+  },
+  "request": {
+    "type": "AudioPlayer.PlaybackFailed",
+    "requestId": "rosai.request.test001",
+    "timestamp": "2018-06-21T05:46:53Z",
+    "token": "story-1",
+    "offsetInMs": 1200,
+    "error": {"type": "DEVICE_COMMUNICATION_ERROR", "message": "timeout"}
+  }
+}`
+
+type storySpeechlet struct {
+	Speechlet
+	events []RequestType
+}
+
+func (ss *storySpeechlet) OnPlaybackStarted(reqEn *RequestEnvelope) (*Response, error) {
+	ss.events = append(ss.events, reqEn.Request.GetType())
+	return nil, nil
+}
+
+func (ss *storySpeechlet) OnPlaybackNearlyFinished(reqEn *RequestEnvelope) (*Response, error) {
+	ss.events = append(ss.events, reqEn.Request.GetType())
+	req := reqEn.Request.(*AudioPlayerRequest)
+	return NewResponse().WithResults(NewResult().AppendDirectives(
+		NewPlayDirective(ENQUEUE, "story-2", "https://ai.roobo.com/story-2.mp3", 0).
+			WithExpectedPreviousToken(req.Token))), nil
+}
+
+func (ss *storySpeechlet) OnPlaybackFinished(reqEn *RequestEnvelope) (*Response, error) {
+	ss.events = append(ss.events, reqEn.Request.GetType())
+	return nil, nil
+}
+
+func (ss *storySpeechlet) OnPlaybackFailed(reqEn *RequestEnvelope) (*Response, error) {
+	ss.events = append(ss.events, reqEn.Request.GetType())
+	return NewResponse().WithResults(NewResult().AppendDirectives(
+		NewClearQueueDirective(CLEAR_ALL))), nil
+}
+
+func TestParseAudioPlayerRequest(t *testing.T) {
+	reqEn, err := ParseRequestEnvelope([]byte(playbackFailedReq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, ok := reqEn.Request.(*AudioPlayerRequest)
+	if !ok {
+		t.Fatalf("want *AudioPlayerRequest, got: %T", reqEn.Request)
+	}
+	if req.GetType() != PlaybackFailedRequestType || req.Token != "story-1" ||
+		req.OffsetInMs != 1200 || req.Err == nil || req.Err.Message != "timeout" {
+		t.Fatalf("got: %+v", req)
+	}
+}
+
+func TestHandleAudioPlayerRequest(t *testing.T) {
+	ss := &storySpeechlet{}
+	handler := &RequestHandler{Speechlet: ss}
+	for _, typ := range []RequestType{PlaybackStartedRequestType,
+		PlaybackNearlyFinishedRequestType, PlaybackFinishedRequestType} {
+		reqEn := NewRequestEnvelope().WithContext(NewContext()).
+			WithRequest(NewAudioPlayerRequest(typ, "req", "ts", "story-1", 0))
+		resp, err := handler.handleAudioPlayerRequest(reqEn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != PlaybackNearlyFinishedRequestType {
+			continue
+		}
+		pd, ok := resp.GetFirstResult().GetDirectives()[0].(*PlayDirective)
+		if !ok || pd.PlayBehavior != ENQUEUE ||
+			pd.GetStream().ExpectedPreviousToken != "story-1" {
+			t.Fatalf("want enqueue story-2 after story-1, got: %+v", resp)
+		}
+	}
+	if len(ss.events) != 3 || ss.events[2] != PlaybackFinishedRequestType {
+		t.Fatalf("got events: %v", ss.events)
+	}
+	// Speechlets without AudioPlayerSpeechlet ignore the requests
+	handler.Speechlet = ss.Speechlet
+	reqEn := NewRequestEnvelope().WithRequest(
+		NewAudioPlayerRequest(PlaybackStartedRequestType, "req", "ts", "story-1", 0))
+	if resp, err := handler.handleAudioPlayerRequest(reqEn); resp != nil || err != nil {
+		t.Fatalf("want nil response, got: %+v, %v", resp, err)
+	}
+}
+
+func TestAudioPlayerDirectives(t *testing.T) {
+	result := NewResult().AppendDirectives(
+		NewPlayDirective(REPLACE_ALL, "story-1", "https://ai.roobo.com/story-1.mp3", 3000),
+		NewStopDirective(), NewClearQueueDirective(CLEAR_ENQUEUED))
+	bytes, _ := json.Marshal(NewResponseEnvelope().WithResults(result))
+	var raw ResponseEnvelopeRaw
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		t.Fatal(err)
+	}
+	dirs := raw.GetResults()[0].GetDirectives()
+	if len(dirs) != 3 {
+		t.Fatalf("want 3 directives, got: %s", bytes)
+	}
+	if pd, ok := dirs[0].(*PlayDirective); !ok || pd.GetStream().OffsetInMs != 3000 {
+		t.Fatalf("want play directive at 3000ms, got: %#v", dirs[0])
+	}
+	if _, ok := dirs[1].(*StopDirective); !ok {
+		t.Fatalf("want stop directive, got: %#v", dirs[1])
+	}
+	if cd, ok := dirs[2].(*ClearQueueDirective); !ok || cd.ClearBehavior != CLEAR_ENQUEUED {
+		t.Fatalf("want clear queue directive, got: %#v", dirs[2])
+	}
+}
@@ -0,0 +1,176 @@
+package speechlet
+
+import (
+	"log"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/interfaces"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+// ResponsePostProcessor changes a response before it is sent back, e.g. to
+// fit the device of the request.
+type ResponsePostProcessor interface {
+	PostProcess(reqEn *RequestEnvelope, resp *Response)
+}
+
+// CapabilityAdapter downgrades the parts of a response the device of the
+// request has not declared among its supported interfaces: H5 scripts
+// become cards with their fallback speech, cards become hints, SSML becomes
+// plain text, and AudioPlayer directives and emotions are dropped. Devices
+// which declare no interfaces get the response unchanged.
+type CapabilityAdapter struct{}
+
+func (CapabilityAdapter) PostProcess(reqEn *RequestEnvelope, resp *Response) {
+	if resp == nil || reqEn == nil {
+		return
+	}
+	dev := reqEn.Context.GetDevice()
+	if dev == nil || dev.SupportedInterfaces == nil {
+		return
+	}
+	// the results and the reprompt are adapted as copies, they may be ones the
+	// speechlet reuses across requests and devices
+	results := make([]*Result, len(resp.Results))
+	for i, v := range resp.Results {
+		if v == nil {
+			continue
+		}
+		r := *v
+		// appending to the copy must not write into a shared backing array
+		r.Directives = r.Directives[:len(r.Directives):len(r.Directives)]
+		adaptScript(dev, &r)
+		adaptDisplay(dev, &r)
+		if !dev.Supports(interfaces.SSMLType) {
+			r.OutputSpeech = downgradeSSML(r.OutputSpeech)
+		}
+		if !dev.Supports(interfaces.AudioPlayerType) {
+			r.removeDirectives(AudioPlayerPlayDirectiveType, AudioPlayerStopDirectiveType,
+				AudioPlayerClearQueueDirectiveType)
+		}
+		if !dev.Supports(interfaces.EmotionType) {
+			r.Emotions = nil
+		}
+		results[i] = &r
+	}
+	if resp.Results != nil {
+		resp.Results = results
+	}
+	if resp.Reprompt != nil {
+		rp := *resp.Reprompt
+		if !dev.Supports(interfaces.SSMLType) {
+			rp.OutputSpeech = downgradeSSML(rp.OutputSpeech)
+		}
+		if rp.Card != nil && !supportsCard(dev, rp.Card) {
+			rp.Card = nil
+		}
+		resp.Reprompt = &rp
+	}
+}
+
+func supportsCard(dev *Device, card ui.CardInterface) bool {
+	if !dev.Supports(interfaces.DisplayType) {
+		return false
+	}
+	display, ok := dev.GetInterface(interfaces.DisplayType).(*interfaces.Display)
+	return !ok || display.SupportsCard(string(card.GetType()))
+}
+
+// adaptScript replaces the H5 items the device can not render by their
+// fallback speech, also shown as a text card.
+func adaptScript(dev *Device, r *Result) {
+	if r.Script == nil {
+		return
+	}
+	h5, declared := dev.GetInterface(interfaces.H5Type).(*interfaces.H5)
+	items := make([]*ui.ScriptItem, 0, len(r.Script.Items))
+	for _, v := range r.Script.Items {
+		if dev.Supports(interfaces.H5Type) && (v.Type != ui.H5TemplateType ||
+			!declared || h5.SupportsTemplate(v.TemplateId)) {
+			items = append(items, v)
+			continue
+		}
+		if v.FallbackSpeech == nil {
+			log.Printf("Warning] device %s can not render %s script item %s, dropped",
+				dev.DeviceId, v.Type, v.TemplateId)
+			continue
+		}
+		var speech []*ui.SpeechItem
+		if r.OutputSpeech != nil {
+			speech = append(speech, r.OutputSpeech.Items...)
+		}
+		r.OutputSpeech = NewSpeechItems(append(speech, v.FallbackSpeech)...)
+		if r.GetDisplayDirective() == nil {
+			text := v.FallbackSpeech.Source
+			if v.FallbackSpeech.Type == ui.SSMLType {
+				text, _ = ui.SSMLToPlainText(text)
+			}
+			r.WithDisplayDirective(NewDisplayDirective().WithCard(ui.NewTextCard(text, "")))
+		}
+	}
+	if len(items) == 0 {
+		r.Script = nil
+		return
+	}
+	r.Script = &ScriptItems{Items: items}
+}
+
+// adaptDisplay turns the card the device can not show into the hint of the
+// result, and drops the suggestions of devices without a screen.
+func adaptDisplay(dev *Device, r *Result) {
+	if !dev.Supports(interfaces.DisplayType) {
+		r.Suggestions = nil
+	}
+	dd := r.GetDisplayDirective()
+	if dd == nil || (dd.Card != nil && supportsCard(dev, dd.Card)) ||
+		(dd.Card == nil && dev.Supports(interfaces.DisplayType)) {
+		return
+	}
+	if r.Hint == "" {
+		r.Hint = dd.Hint
+	}
+	if r.Hint == "" && dd.Card != nil {
+		r.Hint = ui.CardText(dd.Card)
+	}
+	r.removeDirectives(DisplayDirectiveType)
+}
+
+// downgradeSSML returns the speech with the SSML items replaced by the text
+// they read out, dropping the ones which are not well-formed.
+func downgradeSSML(speech *SpeechItems) *SpeechItems {
+	if speech == nil {
+		return nil
+	}
+	items := make([]*ui.SpeechItem, 0, len(speech.Items))
+	for _, v := range speech.Items {
+		if v.Type == ui.SSMLType {
+			text, err := ui.SSMLToPlainText(v.Source)
+			if err != nil {
+				log.Printf("Warning] SSML %s dropped: %s", v.Source, err)
+				continue
+			}
+			v = ui.NewPlainTextSpeechItem(text)
+		}
+		items = append(items, v)
+	}
+	return NewSpeechItems(items...)
+}
+
+func (r *Result) removeDirectives(types ...DirectiveType) {
+	dirs := make([]Directive, 0, len(r.Directives))
+	for _, v := range r.Directives {
+		removed := false
+		for _, t := range types {
+			if v.GetType() == t {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			dirs = append(dirs, v)
+		}
+	}
+	r.Directives = dirs
+	if len(dirs) == 0 {
+		r.Directives = nil
+	}
+}
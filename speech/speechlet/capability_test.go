@@ -0,0 +1,127 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/interfaces"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+func TestDeviceSupportedInterfaces(t *testing.T) {
+	var dev Device
+	err := json.Unmarshal([]byte(`{"deviceId":"d","supportedInterfaces":{`+
+		`"Display":{"cardTypes":["Text"]},"SSML":{},"Projector":{"lumens":300}}}`), &dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	display, ok := dev.GetInterface(interfaces.DisplayType).(*interfaces.Display)
+	if !ok || !display.SupportsCard("Text") || display.SupportsCard("List") {
+		t.Fatalf("want display of text cards, got: %#v", dev.GetInterface(interfaces.DisplayType))
+	}
+	if !dev.Supports(interfaces.SSMLType) || dev.Supports(interfaces.H5Type) {
+		t.Fatalf("want SSML only, got: %+v", dev.SupportedInterfaces)
+	}
+	// unknown interfaces are kept as received
+	bytes, _ := json.Marshal(&dev)
+	var raw struct {
+		SupportedInterfaces map[string]json.RawMessage `json:"supportedInterfaces"`
+	}
+	json.Unmarshal(bytes, &raw)
+	if string(raw.SupportedInterfaces["Projector"]) != `{"lumens":300}` {
+		t.Fatalf("want Projector kept, got: %s", bytes)
+	}
+	if !NewDevice("d").Supports(interfaces.H5Type) {
+		t.Fatal("want every interface supported when none declared")
+	}
+}
+
+func TestCapabilityAdapter(t *testing.T) {
+	item, err := ui.NewH5TemplateScriptItem("weather", "1.0", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item.WithFallbackSpeech("明天晴")
+	result := NewResult().WithOutputSsmlSpeech(ui.SSML().Say("你好").Break(0).String()).
+		WithScriptItems(item).WithEmotions(NewEmotionWithEmotionCode(Happy)).
+		WithTextSuggestions("明天呢").
+		AppendDirectives(NewPlayDirective(REPLACE_ALL, "t", "http://a.mp3", 0))
+	resp := NewResponse().WithResults(result)
+	dev := NewDevice("d").WithInterfaces(&interfaces.AudioPlayer{})
+	reqEn := NewRequestEnvelope().WithContext(NewContext().WithSystem(
+		NewCtxSystem().WithDevice(dev)))
+	CapabilityAdapter{}.PostProcess(reqEn, resp)
+	result = resp.Results[0]
+	speech := result.OutputSpeech.Items
+	if len(speech) != 2 || speech[0].Type != ui.PlainTextType || speech[0].Source != "你好" ||
+		speech[1].Source != "明天晴" {
+		t.Fatalf("want plain text and fallback speech, got: %+v", result.OutputSpeech)
+	}
+	// the H5 item became a card, then a hint without display
+	if result.Script != nil || result.GetDisplayDirective() != nil || result.Hint != "明天晴" {
+		t.Fatalf("want H5 downgraded to hint, got: %+v", result)
+	}
+	if result.Emotions != nil || result.Suggestions != nil || len(result.Directives) != 1 {
+		t.Fatalf("want emotions and suggestions dropped, play kept, got: %+v", result)
+	}
+	// list cards downgraded to hints on text-only displays
+	result = NewResult().WithDisplayDirective(NewDisplayDirective().WithCard(ui.NewListCard(
+		ui.NewStandardCard("晴", ""), ui.NewStandardCard("雨", ""))))
+	dev.WithInterfaces(&interfaces.Display{CardTypes: []string{"Text"}})
+	resp = NewResponse().WithResults(result)
+	CapabilityAdapter{}.PostProcess(reqEn, resp)
+	result = resp.Results[0]
+	if result.GetDisplayDirective() != nil || result.Hint != "晴，雨" {
+		t.Fatalf("want list card hint, got: %+v", result)
+	}
+}
+
+func TestCapabilityAdapterSharedResult(t *testing.T) {
+	// a canned result and reprompt the speechlet reuses across requests
+	item, _ := ui.NewH5TemplateScriptItem("weather", "1.0", map[string]string{})
+	item.WithFallbackSpeech("明天晴")
+	card := ui.NewTextCard("晴", "")
+	play := NewPlayDirective(REPLACE_ALL, "t", "http://a.mp3", 0)
+	dirs := make([]Directive, 1, 4)
+	dirs[0] = play
+	result := NewResult().WithOutputSsmlSpeech(ui.SSML().Say("你好").String()).
+		WithScriptItems(item).WithEmotions(NewEmotionWithEmotionCode(Happy)).
+		WithTextSuggestions("明天呢")
+	result.Directives = dirs
+	reprompt := NewReprompt(ui.NewPlainTextSpeechItem("还在吗?"))
+	reprompt.Card = card
+	send := func(dev *Device) *Response {
+		reqEn := NewRequestEnvelope().WithContext(NewContext().WithSystem(
+			NewCtxSystem().WithDevice(dev)))
+		resp := NewResponse().WithResults(result)
+		resp.Reprompt = reprompt
+		CapabilityAdapter{}.PostProcess(reqEn, resp)
+		return resp
+	}
+
+	screenless := send(NewDevice("speaker").WithInterfaces(&interfaces.SSML{}))
+	if r := screenless.Results[0]; r.Script != nil || r.Hint != "明天晴" ||
+		r.Suggestions != nil || r.Emotions != nil || len(r.Directives) != 0 {
+		t.Fatalf("want the result adapted to the speaker, got: %+v", r)
+	}
+	if screenless.Reprompt.Card != nil {
+		t.Fatalf("want the reprompt card dropped, got: %+v", screenless.Reprompt)
+	}
+	if result.Script == nil || result.Hint != "" || result.Suggestions == nil ||
+		result.Emotions == nil || len(result.Directives) != 1 || dirs[:2][1] != nil ||
+		reprompt.Card != card {
+		t.Fatalf("want the shared result and reprompt untouched, got: %+v, %+v",
+			result, reprompt)
+	}
+
+	display := send(NewDevice("screen").WithInterfaces(&interfaces.Display{},
+		&interfaces.H5{}, &interfaces.SSML{}, &interfaces.AudioPlayer{},
+		&interfaces.Emotion{}))
+	if r := display.Results[0]; r.Script == nil || r.Suggestions == nil ||
+		r.Emotions == nil || len(r.Directives) != 1 || r.Directives[0] != play {
+		t.Fatalf("want the result kept for the display, got: %+v", r)
+	}
+	if display.Reprompt.Card != card {
+		t.Fatalf("want the reprompt card kept, got: %+v", display.Reprompt)
+	}
+}
@@ -0,0 +1,104 @@
+package speechlet
+
+import (
+	"encoding/gob"
+	"time"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func init() {
+	gob.Register(&CarryOverSlots{})
+}
+
+// CarriedSlot is a slot value kept in the session to be inherited by the
+// intents sharing it.
+type CarriedSlot struct {
+	Slot        *slu.Slot `json:"slot"`
+	Intent      string    `json:"intent"`
+	Turn        int       `json:"turn"`
+	TimestampMs int64     `json:"timestampMs"`
+}
+
+// CarryOverSlots holds the shared slot values of a session, together with
+// the number of intent requests seen so far.
+type CarryOverSlots struct {
+	Turn  int                     `json:"turn"`
+	Slots map[string]*CarriedSlot `json:"slots,omitempty"`
+}
+
+func NewCarryOverSlots() *CarryOverSlots {
+	return &CarryOverSlots{Slots: make(map[string]*CarriedSlot)}
+}
+
+// NextTurn counts a new intent request.
+func (cos *CarryOverSlots) NextTurn() {
+	cos.Turn++
+}
+
+// Remember stores the slots of the intent shared by a carry-over rule. Slots
+// which were inherited themselves keep their original age.
+func (cos *CarryOverSlots) Remember(intent *slu.Intent, dm *model.DialogModel) {
+	if intent == nil {
+		return
+	}
+	if cos.Slots == nil {
+		cos.Slots = make(map[string]*CarriedSlot)
+	}
+	now := nowInMs()
+	for _, v := range intent.Slots {
+		if !v.HasValue() || v.IsInherited() || dm.GetCarryOver(intent.Name, v.Name) == nil {
+			continue
+		}
+		slot := *v
+		cos.Slots[v.Name] = &CarriedSlot{
+			Slot:        &slot,
+			Intent:      intent.Name,
+			Turn:        cos.Turn,
+			TimestampMs: now,
+		}
+	}
+}
+
+// Inherit fills the empty slots of the intent with the shared values still
+// alive, marking them as inherited. Expired values are dropped.
+func (cos *CarryOverSlots) Inherit(intent *slu.Intent, dm *model.DialogModel) {
+	if intent == nil || len(cos.Slots) == 0 {
+		return
+	}
+	now := nowInMs()
+	for name, carried := range cos.Slots {
+		rule := dm.GetCarryOver(carried.Intent, name)
+		if !rule.Alive(cos.Turn-carried.Turn, now-carried.TimestampMs) {
+			delete(cos.Slots, name)
+			continue
+		}
+		if !rule.Covers(intent.Name, name) {
+			continue
+		}
+		if slot, ok := intent.Slots[name]; !ok || slot.HasValue() {
+			continue
+		}
+		slot := *carried.Slot
+		intent.SetSlot(slot.WithInherited(true))
+	}
+}
+
+// GetCarryOverSlots returns the shared slot values of the session, creating
+// an empty set on first use.
+func (ss *Session) GetCarryOverSlots() *CarryOverSlots {
+	if ss.Attributes == nil {
+		ss.Attributes = make(map[string]interface{})
+	}
+	if v, ok := ss.Attributes[SSK_CARRY_OVER].(*CarryOverSlots); ok && v != nil {
+		return v
+	}
+	cos := NewCarryOverSlots()
+	ss.Attributes[SSK_CARRY_OVER] = cos
+	return cos
+}
+
+func nowInMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,78 @@
+package speechlet
+
+import (
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func getCarryOverDialogModel() *model.DialogModel {
+	return model.NewDialogModel().WithDialog(model.Dialog{
+		Intents: []*model.Intent{
+			model.NewIntent("SearchOneDay", false).WithSlots(
+				model.NewSlot("city", "ROSAI.ZH_CITY", false, false),
+				model.NewSlot("date", "ROSAI.DATE", false, false)),
+			model.NewIntent("SearchDays", false).WithSlots(
+				model.NewSlot("city", "ROSAI.ZH_CITY", false, false),
+				model.NewSlot("duration", "ROSAI.DURATION", false, false)),
+		},
+		CarryOver: []*model.CarryOver{
+			model.NewCarryOver("city", "SearchOneDay", "SearchDays").WithTurns(2),
+		},
+	})
+}
+
+func TestCarryOverSlots(t *testing.T) {
+	dm := getCarryOverDialogModel()
+	cos := NewCarryOverSlots()
+	// turn 1: city and date said for SearchOneDay
+	cos.NextTurn()
+	cos.Remember(slu.NewIntent("SearchOneDay").
+		WithSlot(slu.NewSlot("city").WithStringValue("北京")).
+		WithSlot(slu.NewSlot("date").WithStringValue("2018-06-21")), dm)
+	if len(cos.Slots) != 1 || cos.Slots["city"] == nil {
+		t.Fatalf("only city should be remembered, got: %+v", cos.Slots)
+	}
+	// turn 2: SearchDays inherits city
+	cos.NextTurn()
+	intent := slu.NewIntentFromModel(dm, "SearchDays")
+	cos.Inherit(intent, dm)
+	city := intent.GetSlot("city")
+	if city.GetStringValue() != "北京" || !city.IsInherited() {
+		t.Fatalf("city want inherited 北京, got: %+v", city)
+	}
+	if intent.GetSlot("duration").HasValue() {
+		t.Fatalf("duration should not be inherited, got: %+v", intent.GetSlot("duration"))
+	}
+	// inherited slots are not remembered again
+	cos.Remember(intent, dm)
+	if cos.Slots["city"].Turn != 1 {
+		t.Fatalf("inherited city should keep turn 1, got: %d", cos.Slots["city"].Turn)
+	}
+	// a slot said by the user is not overridden
+	cos.NextTurn()
+	intent = slu.NewIntentFromModel(dm, "SearchOneDay")
+	intent.SetSlot(slu.NewSlot("city").WithStringValue("上海"))
+	cos.Inherit(intent, dm)
+	if intent.GetSlot("city").GetStringValue() != "上海" || intent.GetSlot("city").IsInherited() {
+		t.Fatalf("city want 上海, got: %+v", intent.GetSlot("city"))
+	}
+	// turn 4: city has expired
+	cos.NextTurn()
+	intent = slu.NewIntentFromModel(dm, "SearchDays")
+	cos.Inherit(intent, dm)
+	if intent.GetSlot("city").HasValue() || len(cos.Slots) != 0 {
+		t.Fatalf("city should be expired, got: %+v", intent.GetSlot("city"))
+	}
+}
+
+func TestCarryOverAlive(t *testing.T) {
+	co := model.NewCarryOver("city", "SearchOneDay").WithTurns(3).WithLifespanInMs(60000)
+	if !co.Alive(3, 60000) || co.Alive(4, 0) || co.Alive(0, 60001) {
+		t.Fatalf("carry over %+v alive check failed", co)
+	}
+	if !model.NewCarryOver("city").Alive(100, 1e9) {
+		t.Fatal("carry over without limits should be alive")
+	}
+}
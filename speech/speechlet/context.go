@@ -21,6 +21,8 @@ const (
 
 type CtxParams map[string]*slu.Value
 
+var _ slu.ParamGetter = CtxParams(nil)
+
 func NewCtxParams() CtxParams {
 	return make(CtxParams)
 }
@@ -136,12 +138,114 @@ func (pa CtxParams) GetMapArrayValue(k string) []map[string]interface{} {
 	return ma
 }
 
+// The As*, Must* and *OrDefault accessors convert the parameter between
+// compatible value types, see slu.Value.AsInt.
+
+func (pa CtxParams) AsInt(k string) (int, error) {
+	return pa.GetParameter(k).AsInt()
+}
+
+func (pa CtxParams) MustInt(k string) int {
+	return pa.GetParameter(k).MustInt()
+}
+
+func (pa CtxParams) IntOrDefault(k string, def int) int {
+	return pa.GetParameter(k).IntOrDefault(def)
+}
+
+func (pa CtxParams) AsFloat(k string) (float64, error) {
+	return pa.GetParameter(k).AsFloat()
+}
+
+func (pa CtxParams) MustFloat(k string) float64 {
+	return pa.GetParameter(k).MustFloat()
+}
+
+func (pa CtxParams) FloatOrDefault(k string, def float64) float64 {
+	return pa.GetParameter(k).FloatOrDefault(def)
+}
+
+func (pa CtxParams) AsBool(k string) (bool, error) {
+	return pa.GetParameter(k).AsBool()
+}
+
+func (pa CtxParams) MustBool(k string) bool {
+	return pa.GetParameter(k).MustBool()
+}
+
+func (pa CtxParams) BoolOrDefault(k string, def bool) bool {
+	return pa.GetParameter(k).BoolOrDefault(def)
+}
+
+func (pa CtxParams) AsString(k string) (string, error) {
+	return pa.GetParameter(k).AsString()
+}
+
+func (pa CtxParams) MustString(k string) string {
+	return pa.GetParameter(k).MustString()
+}
+
+func (pa CtxParams) StringOrDefault(k string, def string) string {
+	return pa.GetParameter(k).StringOrDefault(def)
+}
+
+func (pa CtxParams) AsArray(k string) ([]interface{}, error) {
+	return pa.GetParameter(k).AsArray()
+}
+
+func (pa CtxParams) MustArray(k string) []interface{} {
+	return pa.GetParameter(k).MustArray()
+}
+
+func (pa CtxParams) ArrayOrDefault(k string, def []interface{}) []interface{} {
+	return pa.GetParameter(k).ArrayOrDefault(def)
+}
+
+func (pa CtxParams) AsStrArray(k string) ([]string, error) {
+	return pa.GetParameter(k).AsStrArray()
+}
+
+func (pa CtxParams) MustStrArray(k string) []string {
+	return pa.GetParameter(k).MustStrArray()
+}
+
+func (pa CtxParams) StrArrayOrDefault(k string, def []string) []string {
+	return pa.GetParameter(k).StrArrayOrDefault(def)
+}
+
+func (pa CtxParams) AsMap(k string) (map[string]interface{}, error) {
+	return pa.GetParameter(k).AsMap()
+}
+
+func (pa CtxParams) MustMap(k string) map[string]interface{} {
+	return pa.GetParameter(k).MustMap()
+}
+
+func (pa CtxParams) MapOrDefault(k string, def map[string]interface{}) map[string]interface{} {
+	return pa.GetParameter(k).MapOrDefault(def)
+}
+
+func (pa CtxParams) AsMapArray(k string) ([]map[string]interface{}, error) {
+	return pa.GetParameter(k).AsMapArray()
+}
+
+func (pa CtxParams) MustMapArray(k string) []map[string]interface{} {
+	return pa.GetParameter(k).MustMapArray()
+}
+
+func (pa CtxParams) MapArrayOrDefault(k string, def []map[string]interface{}) []map[string]interface{} {
+	return pa.GetParameter(k).MapArrayOrDefault(def)
+}
+
 type Context struct {
 	System *System `json:"system,omitempty"`
 
 	Context      string `json:"context,omitempty"`
 	LifespanInMs int64  `json:"lifespanInMs,omitempty"`
 	CtxParams    `json:"parameters,omitempty"`
+	// Lifespans of the parameters, parameters without one live as long as
+	// the context.
+	Lifespans map[string]*ParamLifespan `json:"lifespans,omitempty"`
 }
 
 func NewContext() *Context {
@@ -181,6 +285,31 @@ func (sys *System) WithParameters(params CtxParams) *System {
 	return sys
 }
 
+func (sys *System) WithParameter(k string, v *slu.Value) *System {
+	sys.CtxParams = sys.CtxParams.SetParameter(k, v)
+	return sys
+}
+
+func (sys *System) WithIntValue(k string, v int) *System {
+	sys.CtxParams = sys.CtxParams.SetIntValue(k, v)
+	return sys
+}
+
+func (sys *System) WithBoolValue(k string, v bool) *System {
+	sys.CtxParams = sys.CtxParams.SetBoolValue(k, v)
+	return sys
+}
+
+func (sys *System) WithFloatValue(k string, v float64) *System {
+	sys.CtxParams = sys.CtxParams.SetFloatValue(k, v)
+	return sys
+}
+
+func (sys *System) WithStringValue(k, v string) *System {
+	sys.CtxParams = sys.CtxParams.SetStringValue(k, v)
+	return sys
+}
+
 func (ctx *Context) GetSysParameters() CtxParams {
 	return ctx.System.CtxParams
 }
@@ -193,6 +322,16 @@ func (ctx *Context) SetSysParameter(k string, v *slu.Value) {
 	ctx.System.CtxParams = ctx.System.SetParameter(k, v)
 }
 
+// WithSysParameter sets a parameter of the system, which is created if
+// needed.
+func (ctx *Context) WithSysParameter(k string, v *slu.Value) *Context {
+	if ctx.System == nil {
+		ctx.System = NewCtxSystem()
+	}
+	ctx.System.WithParameter(k, v)
+	return ctx
+}
+
 func (ctx *Context) DelSysParameter(k string) {
 	delete(ctx.System.CtxParams, k)
 }
@@ -254,6 +393,63 @@ func (dev *Device) WithSupportedInterfaces(m map[string]interfaces.Interface) *D
 	return dev
 }
 
+// WithInterfaces declares the interfaces supported, keyed by their types.
+func (dev *Device) WithInterfaces(is ...interfaces.Interface) *Device {
+	if dev.SupportedInterfaces == nil {
+		dev.SupportedInterfaces = make(map[string]interfaces.Interface)
+	}
+	for _, v := range is {
+		dev.SupportedInterfaces[v.GetType()] = v
+	}
+	return dev
+}
+
+func (dev *Device) GetInterface(typ string) interfaces.Interface {
+	if dev == nil {
+		return nil
+	}
+	return dev.SupportedInterfaces[typ]
+}
+
+// Supports tells whether the device supports the interface, which is taken
+// for granted when it has not declared its interfaces.
+func (dev *Device) Supports(typ string) bool {
+	if dev == nil || dev.SupportedInterfaces == nil {
+		return true
+	}
+	_, ok := dev.SupportedInterfaces[typ]
+	return ok
+}
+
+// UnmarshalJSON decodes the supported interfaces into the types registered
+// for them.
+func (dev *Device) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		DeviceId            string          `json:"deviceId"`
+		SupportedInterfaces json.RawMessage `json:"supportedInterfaces,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	dev.DeviceId, dev.SupportedInterfaces = raw.DeviceId, nil
+	if len(raw.SupportedInterfaces) == 0 {
+		return nil
+	}
+	m, err := interfaces.UnmarshalInterfaces(raw.SupportedInterfaces)
+	if err != nil {
+		return err
+	}
+	dev.SupportedInterfaces = m
+	return nil
+}
+
+func (ctx *Context) GetDevice() *Device {
+	if ctx == nil || ctx.System == nil {
+		return nil
+	}
+	return ctx.System.Device
+}
+
 func (ctx *Context) GetUserId() string {
 	if ctx == nil || ctx.System == nil || ctx.System.User == nil {
 		return ""
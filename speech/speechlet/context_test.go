@@ -168,6 +168,23 @@ func TestSetGetLastInputSlots(t *testing.T) {
 	}
 }
 
+func TestCtxParamsCoercion(t *testing.T) {
+	ctx := NewContext().WithStringValue("count", "6").WithIntValue("days", 3)
+	if n, err := ctx.AsInt("count"); err != nil || n != 6 {
+		t.Fatalf("want count 6, got: %d, %v", n, err)
+	}
+	if ctx.StringOrDefault("days", "") != "3" {
+		t.Fatalf("want days \"3\", got: %s", ctx.StringOrDefault("days", ""))
+	}
+	if _, err := ctx.AsString("city"); err != slu.NilErr {
+		t.Fatalf("want NilErr for missing parameter, got: %v", err)
+	}
+	var params CtxParams
+	if params.IntOrDefault("count", 1) != 1 {
+		t.Fatal("nil params should return the default")
+	}
+}
+
 func TestParseContextFromBytes(t *testing.T) {
 	var respEnv ResponseEnvelope
 	if err := json.Unmarshal([]byte(respEnvelope), &respEnv); err != nil {
@@ -0,0 +1,147 @@
+package speechlet
+
+import (
+	"encoding/gob"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+// maxDialogHistory bounds the number of turns kept in the session.
+const maxDialogHistory = 16
+
+// NoDialogSpeech answers ROSAI.UndoIntent and ROSAI.StartOverIntent when no
+// dialog is in progress and the skill does not handle them.
+var NoDialogSpeech = "当前没有进行中的对话"
+
+func init() {
+	gob.Register(&DialogStateMachine{})
+}
+
+// DialogTurn is a snapshot of the dialog taken before a user turn was applied.
+type DialogTurn struct {
+	Intent       *slu.Intent `json:"intent"`
+	LastPromptId string      `json:"lastPromptId,omitempty"`
+	LastSlot     string      `json:"lastSlot,omitempty"`
+}
+
+// DialogStateMachine records the progress of the managed dialog of a session:
+// which slot was elicited last with which prompt, how many times in a row it
+// has been elicited, and the history of the intent turn by turn so that the
+// user can go back or start over.
+type DialogStateMachine struct {
+	IntentName   string         `json:"intentName,omitempty"`
+	LastPromptId string         `json:"lastPromptId,omitempty"`
+	LastSlot     string         `json:"lastSlot,omitempty"`
+	Attempts     map[string]int `json:"attempts,omitempty"`
+	History      []*DialogTurn  `json:"history,omitempty"`
+}
+
+func NewDialogStateMachine() *DialogStateMachine {
+	return &DialogStateMachine{Attempts: make(map[string]int)}
+}
+
+// Active reports whether a managed dialog is in progress.
+func (dsm *DialogStateMachine) Active() bool {
+	if dsm == nil {
+		return false
+	}
+	return dsm.IntentName != ""
+}
+
+// Begin makes intentName the intent of the dialog. The state is reset if
+// another dialog was in progress.
+func (dsm *DialogStateMachine) Begin(intentName string) {
+	if dsm.IntentName != intentName {
+		dsm.Reset()
+		dsm.IntentName = intentName
+	}
+}
+
+// Push saves the intent as it was before the current user turn.
+func (dsm *DialogStateMachine) Push(intent *slu.Intent) {
+	if intent == nil {
+		intent = slu.NewIntent(dsm.IntentName)
+	}
+	dsm.History = append(dsm.History, &DialogTurn{
+		Intent:       intent.Clone(),
+		LastPromptId: dsm.LastPromptId,
+		LastSlot:     dsm.LastSlot,
+	})
+	if len(dsm.History) > maxDialogHistory {
+		dsm.History = dsm.History[len(dsm.History)-maxDialogHistory:]
+	}
+}
+
+// Undo pops the last saved turn and returns the intent it held, or nil if
+// there is no history. Elicitation attempts start from scratch again.
+func (dsm *DialogStateMachine) Undo() *slu.Intent {
+	if len(dsm.History) == 0 {
+		return nil
+	}
+	turn := dsm.History[len(dsm.History)-1]
+	dsm.History = dsm.History[:len(dsm.History)-1]
+	dsm.LastPromptId, dsm.LastSlot = turn.LastPromptId, turn.LastSlot
+	dsm.ResetAttempts()
+	return turn.Intent
+}
+
+// StartOver drops the history of the current dialog but keeps its intent.
+func (dsm *DialogStateMachine) StartOver() {
+	name := dsm.IntentName
+	dsm.Reset()
+	dsm.IntentName = name
+}
+
+// Reset forgets everything about the dialog.
+func (dsm *DialogStateMachine) Reset() {
+	dsm.IntentName = ""
+	dsm.LastPromptId = ""
+	dsm.LastSlot = ""
+	dsm.ResetAttempts()
+	dsm.History = nil
+}
+
+// RecordPrompt remembers the prompt sent to the user and returns how many
+// times in a row it has been asked. The prompts eliciting a slot are counted
+// by slot name, the others, e.g. confirmations, by prompt id. Asking another
+// slot or prompt starts the count again.
+func (dsm *DialogStateMachine) RecordPrompt(slotName, promptId string) int {
+	key := promptKey(slotName, promptId)
+	if dsm.Attempts == nil || key != promptKey(dsm.LastSlot, dsm.LastPromptId) {
+		dsm.ResetAttempts()
+	}
+	dsm.LastPromptId = promptId
+	dsm.LastSlot = slotName
+	dsm.Attempts[key]++
+	return dsm.Attempts[key]
+}
+
+func promptKey(slotName, promptId string) string {
+	if slotName != "" {
+		return slotName
+	}
+	return promptId
+}
+
+// ResetAttempts forgets how many times the slots have been elicited.
+func (dsm *DialogStateMachine) ResetAttempts() {
+	dsm.Attempts = make(map[string]int)
+}
+
+// GetAttempts returns how many times in a row the slot has been elicited, or
+// the prompt asked if given a prompt id.
+func (dsm *DialogStateMachine) GetAttempts(slotName string) int {
+	if dsm == nil {
+		return 0
+	}
+	return dsm.Attempts[slotName]
+}
+
+// Reprompts returns how many times in a row the slot has been elicited, or
+// the prompt asked, again after the first time.
+func (dsm *DialogStateMachine) Reprompts(slotName string) int {
+	if n := dsm.GetAttempts(slotName); n > 0 {
+		return n - 1
+	}
+	return 0
+}
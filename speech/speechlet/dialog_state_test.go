@@ -0,0 +1,76 @@
+package speechlet
+
+import (
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func TestDialogStateMachineUndo(t *testing.T) {
+	dsm := NewDialogStateMachine()
+	dsm.Begin(IntentPlanMyTrip)
+	// turn 1: nothing collected yet
+	dsm.Push(slu.NewIntent(IntentPlanMyTrip))
+	dsm.RecordPrompt(SlotToCity, "Elicit.Slot.toCity")
+	// turn 2: toCity collected
+	dsm.Push(slu.NewIntent(IntentPlanMyTrip).
+		WithSlot(slu.NewSlot(SlotToCity).WithStringValue("Sanya")))
+	dsm.RecordPrompt(SlotFromCity, "Elicit.Slot.fromCity")
+	if dsm.LastSlot != SlotFromCity || dsm.LastPromptId != "Elicit.Slot.fromCity" {
+		t.Fatalf("last prompt want: %s/%s, got: %s/%s", SlotFromCity,
+			"Elicit.Slot.fromCity", dsm.LastSlot, dsm.LastPromptId)
+	}
+	prev := dsm.Undo()
+	if prev.GetSlot(SlotToCity).GetStringValue() != "Sanya" {
+		t.Fatalf("undo want toCity[Sanya], got: %+v", prev)
+	}
+	if dsm.LastSlot != SlotToCity || len(dsm.History) != 1 {
+		t.Fatalf("undo want last slot %s and 1 turn left, got: %s, %d",
+			SlotToCity, dsm.LastSlot, len(dsm.History))
+	}
+	prev = dsm.Undo()
+	if prev == nil || len(prev.Slots) != 0 {
+		t.Fatalf("undo want empty intent, got: %+v", prev)
+	}
+	if dsm.Undo() != nil {
+		t.Fatal("undo without history should return nil")
+	}
+	// start over keeps the dialog
+	dsm.StartOver()
+	if !dsm.Active() || dsm.IntentName != IntentPlanMyTrip {
+		t.Fatalf("start over want dialog of %s, got: %+v", IntentPlanMyTrip, dsm)
+	}
+	// another intent resets the dialog
+	dsm.Begin("PlanMyActivity")
+	if len(dsm.History) != 0 || dsm.IntentName != "PlanMyActivity" {
+		t.Fatalf("begin want a new dialog, got: %+v", dsm)
+	}
+}
+
+func TestDialogStateMachineReprompts(t *testing.T) {
+	dsm := NewDialogStateMachine()
+	dsm.Begin(IntentPlanMyTrip)
+	for i := 1; i <= 3; i++ {
+		if n := dsm.RecordPrompt(SlotToCity, "Elicit.Slot.toCity"); n != i {
+			t.Fatalf("attempts want: %d, got: %d", i, n)
+		}
+	}
+	if dsm.Reprompts(SlotToCity) != 2 || dsm.Reprompts(SlotFromCity) != 0 {
+		t.Fatalf("reprompts want: 2/0, got: %d/%d",
+			dsm.Reprompts(SlotToCity), dsm.Reprompts(SlotFromCity))
+	}
+	// asking another prompt starts the count again
+	if n := dsm.RecordPrompt("", "Confirm.Intent"); n != 1 || dsm.LastSlot != "" {
+		t.Fatalf("confirmation want 1 attempt, got: %d, %+v", n, dsm)
+	}
+	if dsm.Reprompts(SlotToCity) != 0 {
+		t.Fatalf("reprompts of toCity want 0, got: %d", dsm.Reprompts(SlotToCity))
+	}
+	if n := dsm.RecordPrompt(SlotToCity, "Elicit.Slot.toCity"); n != 1 {
+		t.Fatalf("attempts of toCity asked again want 1, got: %d", n)
+	}
+	dsm.ResetAttempts()
+	if dsm.GetAttempts(SlotToCity) != 0 {
+		t.Fatalf("attempts want 0 after reset, got: %d", dsm.GetAttempts(SlotToCity))
+	}
+}
@@ -1,6 +1,15 @@
 package speechlet
 
-/*
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
 type DirectiveType string
 
 const (
@@ -8,10 +17,68 @@ const (
 	EventDirectiveType   DirectiveType = "ROSAI.EVENT"
 )
 
+// Directive is an instruction to the device carried by a Result.
 type Directive interface {
 	GetType() DirectiveType
 }
 
+var (
+	directiveTypesMu sync.RWMutex
+	directiveTypes   = map[DirectiveType]func() Directive{
+		DisplayDirectiveType: func() Directive { return &DisplayDirective{} },
+		EventDirectiveType:   func() Directive { return &EventDirective{} },
+	}
+)
+
+// RegisterDirectiveType makes UnmarshalDirective decode the directives of
+// type typ into the value returned by newDir, which must be a pointer.
+func RegisterDirectiveType(typ DirectiveType, newDir func() Directive) {
+	directiveTypesMu.Lock()
+	defer directiveTypesMu.Unlock()
+	directiveTypes[typ] = newDir
+}
+
+// UnmarshalDirective decodes a directive into the type registered for its
+// "type". Directives of unknown types are kept as a *RawDirective.
+func UnmarshalDirective(raw []byte) (Directive, error) {
+	var typePre struct {
+		Type DirectiveType `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &typePre); err != nil {
+		return nil, err
+	}
+	directiveTypesMu.RLock()
+	newDir, ok := directiveTypes[typePre.Type]
+	directiveTypesMu.RUnlock()
+	if !ok {
+		log.Printf("Warning] unrecognized directive type: %s", typePre.Type)
+		return &RawDirective{Type: typePre.Type, Raw: append(json.RawMessage(nil), raw...)}, nil
+	}
+	dir := newDir()
+	if err := json.Unmarshal(raw, dir); err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
+// RawDirective is a directive of a type not registered, marshaled back as is.
+type RawDirective struct {
+	Type DirectiveType
+	Raw  json.RawMessage
+}
+
+func (rd *RawDirective) GetType() DirectiveType {
+	return rd.Type
+}
+
+// MarshalJSON returns the directive as received, null if it has none.
+func (rd *RawDirective) MarshalJSON() ([]byte, error) {
+	if len(rd.Raw) == 0 {
+		return []byte("null"), nil
+	}
+	return rd.Raw, nil
+}
+
 type DisplayDirective struct {
 	Type        DirectiveType    `json:"type"`
 	Hint        string           `json:"hint,omitempty"`
@@ -27,48 +94,39 @@ type DisplayDirectiveRaw struct {
 }
 
 func (ddr *DisplayDirectiveRaw) GetCard() ui.CardInterface {
-	var typePre struct {
-		Type string `json:"type"`
-	}
-	var err error
-	if err = json.Unmarshal([]byte(ddr.Card), &typePre); err != nil {
-		log.Printf("DisplayDirectiveRaw %+v GetCard error: %s", ddr, err)
+	if len(ddr.Card) == 0 {
 		return nil
 	}
-	switch ui.CardType(typePre.Type) {
-	case ui.ImagesCardType:
-		var ic ui.ImagesCard
-		if err = json.Unmarshal([]byte(ddr.Card), &ic); err == nil {
-			return &ic
-		}
-	case ui.StandardCardType:
-		var sc ui.StandardCard
-		if err = json.Unmarshal([]byte(ddr.Card), &sc); err == nil {
-			return &sc
-		}
-	case ui.TextCardType:
-		var tc ui.TextCard
-		if err = json.Unmarshal([]byte(ddr.Card), &tc); err == nil {
-			return &tc
-		}
-	case ui.ListCardType:
-		var lc ui.ListCard
-		if err = json.Unmarshal([]byte(ddr.Card), &lc); err == nil {
-			return &lc
-		}
-	default:
-		log.Printf("DisplayDirectiveRaw %+v unrecgnized card type: %s", ddr, typePre.Type)
-	}
+	card, err := ui.UnmarshalCard(ddr.Card)
 	if err != nil {
 		log.Printf("DisplayDirectiveRaw %+v GetCard error: %s", ddr, err)
+		return nil
 	}
-	return nil
+	return card
 }
 
 func NewDisplayDirective() *DisplayDirective {
 	return &DisplayDirective{Type: DisplayDirectiveType}
 }
 
+// UnmarshalJSON decodes the card into the type registered for it.
+func (dd *DisplayDirective) UnmarshalJSON(data []byte) error {
+	var ddr DisplayDirectiveRaw
+	if err := json.Unmarshal(data, &ddr); err != nil {
+		return err
+	}
+	dd.Type, dd.Hint, dd.Suggestions, dd.Card = ddr.Type, ddr.Hint, ddr.Suggestions, nil
+	if len(ddr.Card) == 0 || string(ddr.Card) == "null" {
+		return nil
+	}
+	card, err := ui.UnmarshalCard(ddr.Card)
+	if err != nil {
+		return errors.New(fmt.Sprintf("display directive card: %s", err))
+	}
+	dd.Card = card
+	return nil
+}
+
 func (dd *DisplayDirective) GetType() DirectiveType {
 	return dd.Type
 }
@@ -138,4 +196,3 @@ func (ed *EventDirective) GetEventPeriod() int {
 	}
 	return ed.Event.Period
 }
-*/
@@ -1,6 +1,12 @@
 package speechlet
 
-/*
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
 const (
 	resultWithDisplay = `{
   "directives": [
@@ -171,4 +177,54 @@ func TestUnmarshalDisplayDirective(t *testing.T) {
 	bytes, _ = json.Marshal(card)
 	t.Logf("card: %s", string(bytes))
 }
-*/
+
+func TestUnmarshalDirective(t *testing.T) {
+	dir, err := UnmarshalDirective([]byte(dispRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dd, ok := dir.(*DisplayDirective)
+	if !ok {
+		t.Fatalf("want *DisplayDirective, got: %T", dir)
+	}
+	if _, ok := dd.GetCard().(*ui.StandardCard); !ok {
+		t.Fatalf("want *ui.StandardCard, got: %T", dd.GetCard())
+	}
+	// unknown directives are kept as is
+	unknown := `{"type":"Custom.Unknown","value":1}`
+	if dir, err = UnmarshalDirective([]byte(unknown)); err != nil {
+		t.Fatal(err)
+	}
+	if dir.GetType() != "Custom.Unknown" {
+		t.Fatalf("want Custom.Unknown, got: %s", dir.GetType())
+	}
+	bytes, _ := json.Marshal(NewResult().AppendDirectives(dir))
+	if string(bytes) != `{"directives":[`+unknown+`]}` {
+		t.Fatalf("raw directive round trip, got: %s", bytes)
+	}
+	// a raw directive made without its JSON does not break the response
+	bytes, err = json.Marshal(NewResult().AppendDirectives(&RawDirective{Type: "Custom.Empty"}))
+	if err != nil || string(bytes) != `{"directives":[null]}` {
+		t.Fatalf("want null directive, got: %s, %v", bytes, err)
+	}
+}
+
+type testDirective struct {
+	Type  DirectiveType `json:"type"`
+	Value int           `json:"value"`
+}
+
+func (td *testDirective) GetType() DirectiveType {
+	return td.Type
+}
+
+func TestRegisterDirectiveType(t *testing.T) {
+	RegisterDirectiveType("Custom.Test", func() Directive { return &testDirective{} })
+	dir, err := UnmarshalDirective([]byte(`{"type":"Custom.Test","value":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if td, ok := dir.(*testDirective); !ok || td.Value != 3 {
+		t.Fatalf("want testDirective with value 3, got: %#v", dir)
+	}
+}
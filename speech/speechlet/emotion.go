@@ -1,5 +1,11 @@
 package speechlet
 
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
 type EmotionCode string
 
 const (
@@ -87,9 +93,15 @@ const (
 )
 
 func getLevelCodeByEmotionCode(code EmotionCode) LevelCode {
+	if code == "" {
+		return LPeace
+	}
 	return LevelCode(code[0] - 'A')
 }
 
+// EmotionTypeAnswer is the type of the emotions shown with an answer.
+const EmotionTypeAnswer = "answer"
+
 type Emotion struct {
 	Type  string      `json:"type"`
 	Level LevelCode   `json:"level"`
@@ -98,6 +110,7 @@ type Emotion struct {
 
 func NewEmotionWithEmotionCode(code EmotionCode) *Emotion {
 	return &Emotion{
+		Type:  EmotionTypeAnswer,
 		Level: getLevelCodeByEmotionCode(code),
 		Code:  code,
 	}
@@ -105,7 +118,7 @@ func NewEmotionWithEmotionCode(code EmotionCode) *Emotion {
 
 func NewDefaultEmotion() *Emotion {
 	return &Emotion{
-		Type:  "answer",
+		Type:  EmotionTypeAnswer,
 		Level: 0,
 		Code:  "A001",
 	}
@@ -170,3 +183,66 @@ func Conv2EmotionCode(code int) EmotionCode {
 
 	return EmotionCodeLst[code]
 }
+
+// ValidateEmotionCode returns an error if code is not one of EmotionCodeLst.
+func ValidateEmotionCode(code EmotionCode) error {
+	for _, v := range EmotionCodeLst {
+		if v == code {
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("invalid emotion code: %s", code))
+}
+
+func (r *Result) WithEmotions(es ...*Emotion) *Result {
+	r.Emotions = append(r.Emotions, es...)
+	return r
+}
+
+func (r *Result) GetEmotions() []*Emotion {
+	if r == nil {
+		return nil
+	}
+	return r.Emotions
+}
+
+// EmotionPolicy picks the emotion of the results left without one by the
+// skill and the dialog model, nil keeps them without emotion.
+type EmotionPolicy interface {
+	GetEmotion(reqEn *RequestEnvelope, result *Result) *Emotion
+}
+
+// StaticEmotionPolicy gives every result the same emotion.
+type StaticEmotionPolicy struct {
+	Code EmotionCode
+}
+
+func NewStaticEmotionPolicy(code EmotionCode) *StaticEmotionPolicy {
+	return &StaticEmotionPolicy{Code: code}
+}
+
+func (sep *StaticEmotionPolicy) GetEmotion(reqEn *RequestEnvelope, result *Result) *Emotion {
+	return NewEmotionWithEmotionCode(sep.Code)
+}
+
+// applyEmotionPolicy gives the results without emotion the one of the
+// policy of the handler, if any.
+func (rh *RequestHandler) applyEmotionPolicy(reqEn *RequestEnvelope, results []*Result) {
+	if rh.EmotionPolicy == nil {
+		return
+	}
+	for _, v := range results {
+		if v == nil || len(v.Emotions) > 0 {
+			continue
+		}
+		e := rh.EmotionPolicy.GetEmotion(reqEn, v)
+		if e == nil {
+			continue
+		}
+		if err := ValidateEmotionCode(e.Code); err != nil {
+			log.Printf("Warning] EmotionPolicy %T: %s", rh.EmotionPolicy, err)
+			continue
+		}
+		v.WithEmotions(e)
+	}
+}
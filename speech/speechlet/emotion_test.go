@@ -0,0 +1,62 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+func TestNewEmotionWithEmotionCode(t *testing.T) {
+	e := NewEmotionWithEmotionCode(Sadness)
+	if e.Type != EmotionTypeAnswer || e.Level != LSad || e.Code != Sadness {
+		t.Fatalf("got: %+v", e)
+	}
+	if err := ValidateEmotionCode(Sadness); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []EmotionCode{"", "Z999", "B004"} {
+		if err := ValidateEmotionCode(v); err == nil {
+			t.Fatalf("emotion code %q should be invalid", v)
+		}
+	}
+}
+
+func TestVariationEmotion(t *testing.T) {
+	var prompt model.Prompt
+	if err := json.Unmarshal([]byte(`{"id": "Elicit.Slot.city", "variations": [
+		{"type": "PlainText", "value": ["你想查哪个城市?"], "emotion": "O001"},
+		{"type": "Audio", "value": ["https://ai.roobo.com/a.mp3"], "emotion": "X001"}]}`),
+		&prompt); err != nil {
+		t.Fatal(err)
+	}
+	result := makeResultFromPrompt(&prompt)
+	if es := result.GetEmotions(); len(es) != 1 || es[0].Code != Curious ||
+		es[0].Type != EmotionTypeAnswer {
+		t.Fatalf("want curious emotion only, got: %+v", es)
+	}
+}
+
+func TestEmotionPolicy(t *testing.T) {
+	handler := &RequestHandler{}
+	results := []*Result{NewResult(), NewResult().WithEmotions(NewEmotionWithEmotionCode(Happy))}
+	handler.applyEmotionPolicy(nil, results)
+	if len(results[0].Emotions) != 0 {
+		t.Fatal("no policy should leave results without emotion")
+	}
+	handler.EmotionPolicy = NewStaticEmotionPolicy(Peaceful)
+	handler.applyEmotionPolicy(nil, results)
+	if es := results[0].GetEmotions(); len(es) != 1 || es[0].Code != Peaceful {
+		t.Fatalf("want peaceful emotion, got: %+v", es)
+	}
+	if es := results[1].GetEmotions(); len(es) != 1 || es[0].Code != Happy {
+		t.Fatalf("want happy emotion kept, got: %+v", es)
+	}
+	// invalid emotions of the policy are dropped
+	results = []*Result{NewResult()}
+	handler.EmotionPolicy = NewStaticEmotionPolicy("Z999")
+	handler.applyEmotionPolicy(nil, results)
+	if len(results[0].Emotions) != 0 {
+		t.Fatalf("want invalid emotion dropped, got: %+v", results[0].Emotions)
+	}
+}
@@ -0,0 +1,211 @@
+package speechlet
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+// FallbackApologySpeech is said when the speechlet fails and the dialog
+// model has no model.ErrorPromptId prompt.
+var FallbackApologySpeech = "抱歉，出了点问题，请稍后再试"
+
+// FallbackPolicy tells how the RequestHandler answers when the speechlet
+// fails, nil answers a status 500 envelope without results. The requests
+// failing before the speechlet, e.g. without AppId, always get that one.
+type FallbackPolicy struct {
+	// ApologyPromptId is the prompt of the dialog model said in place of the
+	// error, FallbackApologySpeech if the model has none, "" says nothing.
+	// The apology comes with the status ApiSuccess so that the device says it,
+	// the error is told by the ApologyErrorType and details of the status.
+	ApologyPromptId string
+	// RetryTransient calls the speechlet once more when it fails with a
+	// transient error.
+	RetryTransient bool
+	// NoResult answers a launch or intent request the speechlet has nothing
+	// to say to with the status ApiNoResult.
+	NoResult bool
+	// RecoverPanics turns a panic of the speechlet into an error, logged with
+	// its stack trace.
+	RecoverPanics bool
+}
+
+// NewFallbackPolicy makes a policy with all its fallbacks on.
+func NewFallbackPolicy() *FallbackPolicy {
+	return &FallbackPolicy{
+		ApologyPromptId: model.ErrorPromptId,
+		RetryTransient:  true,
+		NoResult:        true,
+		RecoverPanics:   true,
+	}
+}
+
+func (fp *FallbackPolicy) WithApologyPromptId(id string) *FallbackPolicy {
+	fp.ApologyPromptId = id
+	return fp
+}
+
+func (fp *FallbackPolicy) WithRetryTransient(retry bool) *FallbackPolicy {
+	fp.RetryTransient = retry
+	return fp
+}
+
+func (fp *FallbackPolicy) WithNoResult(noResult bool) *FallbackPolicy {
+	fp.NoResult = noResult
+	return fp
+}
+
+func (fp *FallbackPolicy) WithRecoverPanics(recoverPanics bool) *FallbackPolicy {
+	fp.RecoverPanics = recoverPanics
+	return fp
+}
+
+func (fp *FallbackPolicy) retries(err error) bool {
+	return fp != nil && fp.RetryTransient && IsTransient(err)
+}
+
+// TransientError is an error of the speechlet which may not happen again,
+// e.g. the timeout of a backend service.
+type TransientError struct {
+	Err error
+}
+
+func NewTransientError(err error) *TransientError {
+	return &TransientError{Err: err}
+}
+
+func (te *TransientError) Error() string {
+	return te.Err.Error()
+}
+
+func (te *TransientError) Temporary() bool {
+	return true
+}
+
+// IsTransient tells whether the error is a TransientError, or any error with
+// a Temporary method returning true like net.Error.
+func IsTransient(err error) bool {
+	t, ok := err.(interface {
+		Temporary() bool
+	})
+	return ok && t.Temporary()
+}
+
+// speechletError is an error of the speechlet, or its panic, which the
+// policy may apologize for.
+type speechletError struct {
+	err error
+}
+
+func (se *speechletError) Error() string {
+	return se.err.Error()
+}
+
+// fromSpeechlet tags the error as one of the speechlet.
+func fromSpeechlet(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*speechletError); ok {
+		return err
+	}
+	return &speechletError{err: err}
+}
+
+// causeOf returns the error returned by the speechlet, or err.
+func causeOf(err error) error {
+	if se, ok := err.(*speechletError); ok {
+		return se.err
+	}
+	return err
+}
+
+func NewNoResultStatus() *Status {
+	return &Status{Code: ApiNoResult}
+}
+
+// recoverDispatchCall is dispatchCall turning its panics into errors if the
+// policy recovers them.
+func (rh *RequestHandler) recoverDispatchCall(reqEn *RequestEnvelope) (
+	resp *Response, ctx *Context, err error) {
+	if rh.FallbackPolicy == nil || !rh.FallbackPolicy.RecoverPanics {
+		return rh.dispatchCall(reqEn)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR] Request[%s] speechlet panic: %v\n%s",
+				reqEn.Request.GetRequestId(), r, debug.Stack())
+			resp, ctx, err = nil, nil, fromSpeechlet(errors.New(fmt.Sprintf(
+				"speechlet panic: %v", r)))
+		}
+	}()
+	return rh.dispatchCall(reqEn)
+}
+
+func (rh *RequestHandler) onLaunch(reqEn *RequestEnvelope) (*Response, error) {
+	resp, err := rh.Speechlet.OnLaunch(reqEn)
+	if rh.FallbackPolicy.retries(err) {
+		log.Printf("Warning] Request[%s] retry OnLaunch after error: %s",
+			reqEn.Request.GetRequestId(), err)
+		resp, err = rh.Speechlet.OnLaunch(reqEn)
+	}
+	return resp, fromSpeechlet(err)
+}
+
+func (rh *RequestHandler) onIntent(reqEn *RequestEnvelope) (*Response, *Context, error) {
+	resp, ctx, err := rh.Speechlet.OnIntent(reqEn)
+	if rh.FallbackPolicy.retries(err) {
+		log.Printf("Warning] Request[%s] retry OnIntent after error: %s",
+			reqEn.Request.GetRequestId(), err)
+		resp, ctx, err = rh.Speechlet.OnIntent(reqEn)
+	}
+	return resp, ctx, fromSpeechlet(err)
+}
+
+// applyFallbackPolicy gives the status of the response of the request, and
+// the apology said in place of an error of the speechlet if the policy has
+// one.
+func (rh *RequestHandler) applyFallbackPolicy(reqEn *RequestEnvelope, resp *Response,
+	err error) (*Response, *Status) {
+	fp := rh.FallbackPolicy
+	if err == nil {
+		if fp != nil && fp.NoResult && answersUser(reqEn) &&
+			(resp == nil || len(resp.Results) == 0 && !resp.HasDirectives()) {
+			log.Printf("INFO] Request[%s] has no result", reqEn.Request.GetRequestId())
+			return resp, NewNoResultStatus()
+		}
+		return resp, NewGoodStatus()
+	}
+	log.Printf("Warning] Request: %s, error: %s", reqEn.Request.GetRequestId(), err)
+	if causeOf(err) == ErrServiceMismatched {
+		return resp, NewMismatchStatus(err.Error())
+	}
+	if _, ok := err.(*speechletError); !ok || fp == nil || fp.ApologyPromptId == "" ||
+		!answersUser(reqEn) {
+		return resp, NewInternalErrStatus(err.Error())
+	}
+	return rh.apologyResponse(reqEn, fp.ApologyPromptId), NewApologyStatus(err.Error())
+}
+
+func answersUser(reqEn *RequestEnvelope) bool {
+	switch reqEn.Request.GetType() {
+	case LaunchRequestType, IntentRequestType, IntentsRequestType:
+		return true
+	}
+	return false
+}
+
+func (rh *RequestHandler) apologyResponse(reqEn *RequestEnvelope, promptId string) *Response {
+	dm := rh.DialogModel
+	if dm == nil && rh.DialogModelCallback != nil {
+		dm = rh.DialogModelCallback.GetDialogModel(reqEn.Context)
+	}
+	if prompt := dm.GetRandomPrompt(promptId); prompt != nil {
+		return NewResponse().WithResults(makeResultFromPrompt(prompt)).
+			WithShouldEndSession(false)
+	}
+	return NewAskResponse(FallbackApologySpeech)
+}
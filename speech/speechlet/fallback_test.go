@@ -0,0 +1,154 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+type faultySpeechlet struct {
+	greetSpeechlet
+	calls  int
+	launch func(calls int) (*Response, error)
+}
+
+func (fs *faultySpeechlet) OnLaunch(reqEn *RequestEnvelope) (*Response, error) {
+	fs.calls++
+	return fs.launch(fs.calls)
+}
+
+type fallbackResult struct {
+	Status  Status `json:"status"`
+	Results []struct {
+		OutputSpeech struct {
+			Items []struct {
+				Source string `json:"source"`
+			} `json:"items"`
+		} `json:"outputSpeech"`
+	} `json:"results"`
+}
+
+func (fr *fallbackResult) speech() string {
+	if len(fr.Results) == 0 || len(fr.Results[0].OutputSpeech.Items) == 0 {
+		return ""
+	}
+	return fr.Results[0].OutputSpeech.Items[0].Source
+}
+
+func launchWithFallback(t *testing.T, fp *FallbackPolicy, dm *model.DialogModel,
+	s *faultySpeechlet) *fallbackResult {
+	return callWithFallback(t, fp, dm, s, NewUser("u", "a"),
+		NewLaunchRequest("req1", "2018-04-06T15:30:02+08:00"))
+}
+
+func callWithFallback(t *testing.T, fp *FallbackPolicy, dm *model.DialogModel,
+	s *faultySpeechlet, user *User, req Request) *fallbackResult {
+	handler := &RequestHandler{
+		Speechlet:      s,
+		DialogModel:    dm,
+		SessionStore:   NewMemorySessionStore(),
+		FallbackPolicy: fp,
+	}
+	reqEn := NewRequestEnvelope().WithContext(NewContext().WithSystem(NewCtxSystem().
+		WithUser(user).WithDevice(NewDevice("d")).WithSkill(NewSkill("s")))).
+		WithRequest(req)
+	reqBytes, _ := json.Marshal(reqEn)
+	respBytes, err := handler.HandleCall(reqBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr := new(fallbackResult)
+	if err := json.Unmarshal(respBytes, fr); err != nil {
+		t.Fatal(err)
+	}
+	return fr
+}
+
+func TestFallbackPolicy(t *testing.T) {
+	failed := func(calls int) (*Response, error) {
+		return nil, errors.New("backend down")
+	}
+	flaky := func(calls int) (*Response, error) {
+		if calls == 1 {
+			return nil, NewTransientError(errors.New("backend timeout"))
+		}
+		return NewAskResponse("你好"), nil
+	}
+	panicked := func(calls int) (*Response, error) {
+		var resp *Response
+		return resp.WithShouldEndSession(true), nil
+	}
+	silent := func(calls int) (*Response, error) {
+		return nil, nil
+	}
+	dm := new(model.DialogModel)
+	json.Unmarshal([]byte(`{"prompts": [{"id": "Skill.Error", "variations": [`+
+		`{"type": "PlainText", "value": ["出错了"]}]}]}`), dm)
+	for i, c := range []struct {
+		policy *FallbackPolicy
+		dm     *model.DialogModel
+		launch func(calls int) (*Response, error)
+		code   ApiStatusCode
+		speech string
+		calls  int
+		// apology tells whether the speech apologizes for an error
+		apology bool
+	}{
+		{nil, dm, failed, ApiInternal, "", 1, false},
+		{NewFallbackPolicy(), dm, failed, ApiSuccess, "出错了", 1, true},
+		{NewFallbackPolicy(), &model.DialogModel{}, failed, ApiSuccess,
+			FallbackApologySpeech, 1, true},
+		{NewFallbackPolicy().WithApologyPromptId(""), dm, failed, ApiInternal, "", 1, false},
+		{NewFallbackPolicy(), dm, flaky, ApiSuccess, "你好", 2, false},
+		{NewFallbackPolicy().WithRetryTransient(false), dm, flaky, ApiSuccess, "出错了", 1, true},
+		{NewFallbackPolicy(), dm, panicked, ApiSuccess, "出错了", 1, true},
+		{NewFallbackPolicy(), dm, silent, ApiNoResult, "", 1, false},
+		{nil, dm, silent, ApiSuccess, "", 1, false},
+	} {
+		s := &faultySpeechlet{launch: c.launch}
+		fr := launchWithFallback(t, c.policy, c.dm, s)
+		if fr.Status.Code != c.code || fr.speech() != c.speech || s.calls != c.calls {
+			t.Fatalf("case %d: want %d %q after %d calls, got %d %q after %d calls", i,
+				c.code, c.speech, c.calls, fr.Status.Code, fr.speech(), s.calls)
+		}
+		// the apologies are played with the error in the status
+		if c.apology != (fr.Status.ErrorType == ApologyErrorType &&
+			fr.Status.ErrorDetails != "") {
+			t.Fatalf("case %d: got status %+v", i, fr.Status)
+		}
+	}
+}
+
+func TestFallbackPolicyInvalidRequest(t *testing.T) {
+	dm := new(model.DialogModel)
+	for i, c := range []struct {
+		user *User
+		req  Request
+	}{
+		// no AppId
+		{NewUser("u", ""), NewLaunchRequest("req1", "2018-04-06T15:30:02+08:00")},
+		// intent missing from the dialog model
+		{NewUser("u", "a"), NewIntentRequest("req1", "2018-04-06T15:30:02+08:00",
+			slu.NewIntent("Unknown"))},
+	} {
+		s := &faultySpeechlet{}
+		fr := callWithFallback(t, NewFallbackPolicy(), dm, s, c.user, c.req)
+		if fr.Status.Code != ApiInternal || fr.Status.ErrorType != "" || fr.speech() != "" ||
+			s.calls != 0 {
+			t.Fatalf("case %d: want status 500 without apology, got %+v %q", i, fr.Status,
+				fr.speech())
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	if IsTransient(nil) || IsTransient(errors.New("x")) {
+		t.Fatal("want plain errors not transient")
+	}
+	if !IsTransient(NewTransientError(errors.New("x"))) {
+		t.Fatal("want TransientError transient")
+	}
+}
@@ -0,0 +1,28 @@
+package speechlet
+
+// Built-in intents the platform may send to any skill. They need not be
+// declared in a skill's dialog model, those which are not go to the
+// speechlet without a dialog to handle.
+const (
+	// Ask the skill what it can do.
+	HelpIntent = "ROSAI.HelpIntent"
+	// Leave the skill, or give up the current dialog.
+	StopIntent   = "ROSAI.StopIntent"
+	CancelIntent = "ROSAI.CancelIntent"
+	// Go back to the previous question of a multi-turn dialog.
+	UndoIntent = "ROSAI.UndoIntent"
+	// Forget every slot collected so far and restart the current dialog.
+	StartOverIntent = "ROSAI.StartOverIntent"
+	// Read the next or the previous page of a paginated list.
+	NextIntent     = "ROSAI.NextIntent"
+	PreviousIntent = "ROSAI.PreviousIntent"
+)
+
+func isBuiltinIntent(name string) bool {
+	switch name {
+	case HelpIntent, StopIntent, CancelIntent, UndoIntent, StartOverIntent,
+		NextIntent, PreviousIntent:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package speechlet
+
+import (
+	"encoding/json"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+// ParamLifespan limits how long a context parameter lives: at most MaxTurns
+// requests after it was set, and not after ExpiresAtMs. A zero value leaves
+// the corresponding limit unset.
+type ParamLifespan struct {
+	MaxTurns    int   `json:"maxTurns,omitempty"`
+	Turns       int   `json:"turns,omitempty"`
+	ExpiresAtMs int64 `json:"expiresAtMs,omitempty"`
+}
+
+func NewParamLifespan(turns int, ms int64) *ParamLifespan {
+	ls := &ParamLifespan{MaxTurns: turns}
+	if ms > 0 {
+		ls.ExpiresAtMs = nowInMs() + ms
+	}
+	return ls
+}
+
+// NextTurn counts a request and reports whether the parameter is still alive.
+func (ls *ParamLifespan) NextTurn(nowMs int64) bool {
+	if ls == nil {
+		return true
+	}
+	ls.Turns++
+	if ls.MaxTurns > 0 && ls.Turns > ls.MaxTurns {
+		return false
+	}
+	if ls.ExpiresAtMs > 0 && nowMs > ls.ExpiresAtMs {
+		return false
+	}
+	return true
+}
+
+func (ctx *Context) WithParameterLifespan(k string, turns int, ms int64) *Context {
+	ctx.SetParameterLifespan(k, turns, ms)
+	return ctx
+}
+
+func (ctx *Context) SetParameterLifespan(k string, turns int, ms int64) {
+	if ctx.Lifespans == nil {
+		ctx.Lifespans = make(map[string]*ParamLifespan)
+	}
+	ctx.Lifespans[k] = NewParamLifespan(turns, ms)
+}
+
+func (ctx *Context) setParameterLifespan(k string, ls *ParamLifespan) {
+	if ctx.Lifespans == nil {
+		ctx.Lifespans = make(map[string]*ParamLifespan)
+	}
+	ctx.Lifespans[k] = ls
+}
+
+func (ctx *Context) getParameter(k string) *slu.Value {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.CtxParams[k]
+}
+
+// sameValue reports whether the two values are set and hold the same value.
+func sameValue(a, b *slu.Value) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	x, _ := json.Marshal(a.Value)
+	y, _ := json.Marshal(b.Value)
+	return string(x) == string(y)
+}
+
+func (ctx *Context) GetParameterLifespan(k string) *ParamLifespan {
+	if ctx == nil || ctx.Lifespans == nil {
+		return nil
+	}
+	return ctx.Lifespans[k]
+}
+
+// DelParameter deletes the parameter together with its lifespan.
+func (ctx *Context) DelParameter(k string) {
+	delete(ctx.CtxParams, k)
+	delete(ctx.Lifespans, k)
+}
+
+// ExpireParameters counts a new request for every parameter with a lifespan
+// and deletes the ones which have expired. It is called on the context of
+// every request before it is handed to the Speechlet.
+func (ctx *Context) ExpireParameters() {
+	if ctx == nil {
+		return
+	}
+	now := nowInMs()
+	for k, ls := range ctx.Lifespans {
+		if _, ok := ctx.CtxParams[k]; !ok {
+			delete(ctx.Lifespans, k)
+			continue
+		}
+		if !ls.NextTurn(now) {
+			ctx.DelParameter(k)
+		}
+	}
+}
+
+// applyDefaultLifespan gives the parameters without a lifespan the one of
+// the whole context, if any.
+func (ctx *Context) applyDefaultLifespan() {
+	if ctx == nil || ctx.LifespanInMs <= 0 {
+		return
+	}
+	for k := range ctx.CtxParams {
+		if ctx.GetParameterLifespan(k) == nil {
+			ctx.SetParameterLifespan(k, 0, ctx.LifespanInMs)
+		}
+	}
+}
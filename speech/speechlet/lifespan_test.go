@@ -0,0 +1,104 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func TestExpireParameters(t *testing.T) {
+	ctx := NewContext().
+		WithStringValue("city", "北京").WithParameterLifespan("city", 2, 0).
+		WithStringValue("date", "2018-06-21").WithParameterLifespan("date", 0, 60000).
+		WithStringValue("focus", "天气").WithParameterLifespan("focus", 0, 1).
+		WithStringValue("name", "rosai")
+	ctx.GetParameterLifespan("focus").ExpiresAtMs = nowInMs() - 1
+	// the lifespans go with the context through the platform
+	bytes, _ := json.Marshal(ctx)
+	var got Context
+	if err := json.Unmarshal(bytes, &got); err != nil {
+		t.Fatal(err)
+	}
+	got.ExpireParameters()
+	if got.GetStringValue("city") != "北京" || got.GetStringValue("date") != "2018-06-21" ||
+		got.GetStringValue("focus") != "" || got.GetStringValue("name") != "rosai" {
+		t.Fatalf("turn 1 want city, date and name, got: %s", string(bytes))
+	}
+	if got.GetParameterLifespan("focus") != nil {
+		t.Fatal("lifespan of expired focus should be deleted")
+	}
+	got.ExpireParameters()
+	if got.GetStringValue("city") != "北京" {
+		t.Fatal("turn 2 want city")
+	}
+	got.ExpireParameters()
+	if got.GetStringValue("city") != "" || got.GetStringValue("date") == "" {
+		t.Fatal("turn 3 want city expired and date alive")
+	}
+}
+
+func TestDefaultLifespan(t *testing.T) {
+	ctx := NewContext().WithLifespanInMs(1000).
+		WithStringValue("city", "北京").WithParameterLifespan("city", 1, 0).
+		WithStringValue("date", "2018-06-21")
+	ctx.applyDefaultLifespan()
+	if ls := ctx.GetParameterLifespan("city"); ls.MaxTurns != 1 || ls.ExpiresAtMs != 0 {
+		t.Fatalf("city lifespan should be kept, got: %+v", ls)
+	}
+	if ls := ctx.GetParameterLifespan("date"); ls == nil || ls.ExpiresAtMs == 0 {
+		t.Fatalf("date want the lifespan of the context, got: %+v", ls)
+	}
+}
+
+func TestShareSlotsWithLifespan(t *testing.T) {
+	dm := model.NewDialogModel().WithDialog(model.NewDialog(
+		model.NewIntent("SearchOneDay", false).WithSlots(
+			&model.Slot{Name: "city", ShareLifespan: model.NewLifespan(3, 0)},
+			&model.Slot{Name: "date"},
+			&model.Slot{Name: "token", ConcealRequired: true})))
+	intent := slu.NewIntent("SearchOneDay").
+		WithSlot(slu.NewSlot("city").WithStringValue("北京")).
+		WithSlot(slu.NewSlot("date").WithStringValue("2018-06-21")).
+		WithSlot(slu.NewSlot("token").WithStringValue("secret"))
+	ctx := rh.shareSlotsToContext(intent, nil, nil, nil, dm)
+	if ctx.GetStringValue("token") != "" || ctx.GetStringValue("date") == "" {
+		t.Fatalf("want date shared and token concealed, got: %+v", ctx.CtxParams)
+	}
+	if ls := ctx.GetParameterLifespan("city"); ls == nil || ls.MaxTurns != 3 {
+		t.Fatalf("city want lifespan of 3 turns, got: %+v", ls)
+	}
+	if ctx.GetParameterLifespan("date") != nil {
+		t.Fatal("date should have no lifespan")
+	}
+}
+
+func TestSharedSlotLifespanAcrossTurns(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	intent := tripIntent("toCity", "Paris")
+	intent.GetSlot("toCity").WithStatus(slu.CONFIRMED)
+	req := NewIntentRequest("req-1", "", intent)
+	respEn := handlerCall(t, handler, req, nil)
+	// toCity lives 2 more turns while the dialog of Trip goes on
+	for turn := 0; turn <= 2; turn++ {
+		ls := respEn.Context.GetParameterLifespan("toCity")
+		if ls == nil || ls.Turns != turn || respEn.Context.getParameter("toCity") == nil {
+			t.Fatalf("turn %d: want toCity counted %d turns, got: %+v", turn, turn, ls)
+		}
+		respEn = handlerCall(t, handler, NewIntentRequest("req-1", "", tripIntent()),
+			respEn.Context)
+		if got := envelopeSpeech(respEn); got != "When to Paris?" {
+			t.Fatalf("turn %d: want date elicited, got %q", turn, got)
+		}
+	}
+	if respEn.Context.getParameter("toCity") != nil {
+		t.Fatalf("want toCity expired, got: %+v", respEn.Context.CtxParams)
+	}
+	// a new value is shared again
+	respEn = handlerCall(t, handler, NewIntentRequest("req-1", "", tripIntent("toCity", "Rome")),
+		respEn.Context)
+	if ls := respEn.Context.GetParameterLifespan("toCity"); ls == nil || ls.Turns != 0 {
+		t.Fatalf("want new lifespan of toCity, got: %+v", ls)
+	}
+}
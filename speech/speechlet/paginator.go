@@ -0,0 +1,280 @@
+package speechlet
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+const SSK_PAGINATOR string = "paginator"
+
+const DefaultPageSize = 5
+
+// Speeches of the paginator, {$page} and {$pages} in PaginatorIntro are
+// replaced by the number of the page and the count of pages.
+var (
+	PaginatorIntro           = "第{$page}页，共{$pages}页："
+	PaginatorMoreSpeech      = "说“下一页”听更多"
+	PaginatorFirstPageSpeech = "已经是第一页了"
+	PaginatorLastPageSpeech  = "已经是最后一页了"
+	// PaginatorEmptySpeech is said for a list without items.
+	PaginatorEmptySpeech = "列表是空的"
+	// PaginatorNoListSpeech answers ROSAI.NextIntent and ROSAI.PreviousIntent
+	// when no list is being read and the skill does not handle them.
+	PaginatorNoListSpeech = "当前没有可以翻页的列表"
+)
+
+func init() {
+	gob.Register(&Paginator{})
+}
+
+// PageItem is an entry of a paginated list, said with its speech, or its
+// title when it has none, and shown as a standard card of the list card.
+type PageItem struct {
+	Title   string `json:"title"`
+	Content string `json:"content,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Speech  string `json:"speech,omitempty"`
+}
+
+func NewPageItem(title, content string) *PageItem {
+	return &PageItem{Title: title, Content: content}
+}
+
+func (pi *PageItem) WithImage(url string) *PageItem {
+	pi.Image = url
+	return pi
+}
+
+func (pi *PageItem) WithSpeech(speech string) *PageItem {
+	pi.Speech = speech
+	return pi
+}
+
+func (pi *PageItem) GetSpeech() string {
+	if pi.Speech != "" {
+		return pi.Speech
+	}
+	return pi.Title
+}
+
+// PageLoader loads the items of a page of a list too long to be kept in the
+// session, from the cursor given to NewCursorPaginator.
+type PageLoader func(cursor string, page, pageSize int) ([]*PageItem, error)
+
+var (
+	pageLoaders   = make(map[string]PageLoader)
+	pageLoadersMu sync.RWMutex
+)
+
+// RegisterPageLoader makes the loader available to the cursor paginators
+// created with its name.
+func RegisterPageLoader(name string, loader PageLoader) {
+	pageLoadersMu.Lock()
+	defer pageLoadersMu.Unlock()
+	pageLoaders[name] = loader
+}
+
+func getPageLoader(name string) PageLoader {
+	pageLoadersMu.RLock()
+	defer pageLoadersMu.RUnlock()
+	return pageLoaders[name]
+}
+
+// Paginator reads a long list page by page. It is kept in the session by
+// the RequestHandler once returned with a response, which then turns the
+// ROSAI.NextIntent and ROSAI.PreviousIntent into the next and previous pages
+// until the session ends or another paginator is returned:
+//
+//	return speechlet.NewPaginator(items, 3).Response()
+type Paginator struct {
+	// Items is the whole list, unless Loader is set.
+	Items []*PageItem `json:"items,omitempty"`
+	// Loader is the name of the PageLoader given Cursor, Total is the count
+	// of items it can load.
+	Loader   string `json:"loader,omitempty"`
+	Cursor   string `json:"cursor,omitempty"`
+	Total    int    `json:"total,omitempty"`
+	PageSize int    `json:"pageSize"`
+	// Page is the page read last, from 0.
+	Page  int    `json:"page"`
+	Intro string `json:"intro,omitempty"`
+}
+
+func NewPaginator(items []*PageItem, pageSize int) *Paginator {
+	return &Paginator{Items: items, PageSize: pageSize, Intro: PaginatorIntro}
+}
+
+// NewCursorPaginator makes a paginator of total items loaded page by page by
+// the PageLoader registered with the name.
+func NewCursorPaginator(loader, cursor string, total, pageSize int) *Paginator {
+	return &Paginator{
+		Loader:   loader,
+		Cursor:   cursor,
+		Total:    total,
+		PageSize: pageSize,
+		Intro:    PaginatorIntro,
+	}
+}
+
+func (p *Paginator) WithIntro(intro string) *Paginator {
+	p.Intro = intro
+	return p
+}
+
+func (p *Paginator) GetPageSize() int {
+	if p.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return p.PageSize
+}
+
+func (p *Paginator) Count() int {
+	if p.Loader != "" {
+		return p.Total
+	}
+	return len(p.Items)
+}
+
+func (p *Paginator) Pages() int {
+	size := p.GetPageSize()
+	return (p.Count() + size - 1) / size
+}
+
+func (p *Paginator) HasNext() bool {
+	return p.Page+1 < p.Pages()
+}
+
+func (p *Paginator) HasPrevious() bool {
+	return p.Page > 0
+}
+
+// Render makes page n, from 0, the page read last.
+func (p *Paginator) Render(n int) (*Result, error) {
+	if n < 0 || n >= p.Pages() {
+		return nil, errors.New(fmt.Sprintf("page %d out of range [0, %d)", n, p.Pages()))
+	}
+	items, err := p.pageItems(n)
+	if err != nil {
+		return nil, err
+	}
+	p.Page = n
+	card := ui.NewListCard()
+	speeches := make([]string, 0, len(items))
+	for _, v := range items {
+		sc := ui.NewStandardCard(v.Title, v.Content)
+		if v.Image != "" {
+			sc.WithImage(v.Image)
+		}
+		card.AppendCards(sc)
+		speeches = append(speeches, v.GetSpeech())
+	}
+	speech := strings.Join(speeches, "，")
+	if p.Pages() > 1 {
+		intro := strings.Replace(p.Intro, "{$page}", strconv.Itoa(n+1), -1)
+		intro = strings.Replace(intro, "{$pages}", strconv.Itoa(p.Pages()), -1)
+		speech = intro + speech
+	}
+	if p.HasNext() {
+		speech += "。" + PaginatorMoreSpeech
+	}
+	return NewResult().WithOutputPlainTextSpeech(speech).
+		WithDisplayDirective(NewDisplayDirective().WithCard(card)), nil
+}
+
+func (p *Paginator) pageItems(n int) ([]*PageItem, error) {
+	size := p.GetPageSize()
+	if p.Loader == "" {
+		end := (n + 1) * size
+		if end > len(p.Items) {
+			end = len(p.Items)
+		}
+		return p.Items[n*size : end], nil
+	}
+	loader := getPageLoader(p.Loader)
+	if loader == nil {
+		return nil, errors.New(fmt.Sprintf("page loader %s not registered", p.Loader))
+	}
+	return loader(p.Cursor, n, size)
+}
+
+// Response renders the first page in a response keeping the session open,
+// the paginator is saved in the session with it. An empty list is told
+// PaginatorEmptySpeech and not saved.
+func (p *Paginator) Response() (*Response, error) {
+	if p.Count() == 0 {
+		return NewResponse().WithResults(NewResult().
+			WithOutputPlainTextSpeech(PaginatorEmptySpeech)).WithShouldEndSession(false), nil
+	}
+	result, err := p.Render(0)
+	if err != nil {
+		return nil, err
+	}
+	return NewResponse().WithResults(result).WithShouldEndSession(false).
+		WithPaginator(p), nil
+}
+
+func (resp *Response) WithPaginator(p *Paginator) *Response {
+	resp.Paginator = p
+	return resp
+}
+
+func (ss *Session) WithPaginator(p *Paginator) *Session {
+	return ss.WithAttr(SSK_PAGINATOR, p)
+}
+
+func (ss *Session) GetPaginator() *Paginator {
+	if v, ok := ss.Attributes[SSK_PAGINATOR].(*Paginator); ok {
+		return v
+	}
+	return nil
+}
+
+func (ss *Session) ClearPaginator() {
+	delete(ss.Attributes, SSK_PAGINATOR)
+}
+
+// Next makes the page after the one read last, or says it is the last.
+func (p *Paginator) Next() (*Result, error) {
+	if !p.HasNext() {
+		return NewResult().WithOutputPlainTextSpeech(PaginatorLastPageSpeech), nil
+	}
+	return p.Render(p.Page + 1)
+}
+
+// Previous makes the page before the one read last, or says it is the first.
+func (p *Paginator) Previous() (*Result, error) {
+	if !p.HasPrevious() {
+		return NewResult().WithOutputPlainTextSpeech(PaginatorFirstPageSpeech), nil
+	}
+	return p.Render(p.Page - 1)
+}
+
+// handlePagination reads the page after or before the one read last.
+func (rh *RequestHandler) handlePagination(req *IntentRequest, session *Session,
+	p *Paginator) (*Response, *Context, error) {
+	var (
+		result *Result
+		err    error
+	)
+	if req.IntentName() == PreviousIntent {
+		result, err = p.Previous()
+	} else {
+		result, err = p.Next()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("INFO] Request[%s] %s to page %d of %d", req.GetRequestId(),
+		req.IntentName(), p.Page+1, p.Pages())
+	if err := rh.saveSession(session); err != nil {
+		log.Printf("Warning] saveSession[%s] error: %s", req.GetRequestId(), err)
+	}
+	return NewResponse().WithResults(result).WithShouldEndSession(false), nil, nil
+}
@@ -0,0 +1,156 @@
+package speechlet
+
+import (
+	"fmt"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+func makePageItems(n int) []*PageItem {
+	items := make([]*PageItem, n)
+	for i := range items {
+		items[i] = NewPageItem(fmt.Sprintf("第%d天", i+1), "晴")
+	}
+	return items
+}
+
+func TestPaginatorRender(t *testing.T) {
+	p := NewPaginator(makePageItems(7), 3)
+	if p.Pages() != 3 {
+		t.Fatalf("want 3 pages, got: %d", p.Pages())
+	}
+	result, err := p.Render(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := result.GetFirstOutputPlainTextSpeech(); text != "第3页，共3页：第7天" {
+		t.Fatalf("want last page speech, got: %s", text)
+	}
+	card, ok := result.GetDisplayDirective().GetCard().(*ui.ListCard)
+	if !ok || len(card.List) != 1 || card.List[0].Title != "第7天" {
+		t.Fatalf("want list card of 1 item, got: %#v", result.GetDisplayDirective())
+	}
+	if _, err := p.Render(3); err == nil {
+		t.Fatal("want page out of range error")
+	}
+}
+
+func TestCursorPaginator(t *testing.T) {
+	RegisterPageLoader("days", func(cursor string, page, pageSize int) ([]*PageItem, error) {
+		return makePageItems(30)[page*pageSize : (page+1)*pageSize], nil
+	})
+	p := NewCursorPaginator("days", "c", 30, 0)
+	result, err := p.Render(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := result.GetFirstOutputPlainTextSpeech(); text !=
+		"第2页，共6页：第6天，第7天，第8天，第9天，第10天。"+PaginatorMoreSpeech {
+		t.Fatalf("want second page speech, got: %s", text)
+	}
+	if _, err := NewCursorPaginator("none", "", 1, 1).Render(0); err == nil {
+		t.Fatal("want loader not registered error")
+	}
+}
+
+func TestPaginatorNavigation(t *testing.T) {
+	session := NewSession("u", "a", "d", "s")
+	session.WithPaginator(NewPaginator(makePageItems(4), 2))
+	// the paginator survives the session store
+	bytes, err := GobSerializer{}.Serialize(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session = NewSession("u", "a", "d", "s")
+	if err := (GobSerializer{}).Deserialize(bytes, session); err != nil {
+		t.Fatal(err)
+	}
+	p := session.GetPaginator()
+	for i, v := range []struct {
+		next   bool
+		speech string
+	}{
+		{true, "第2页，共2页：第3天，第4天"},
+		{true, PaginatorLastPageSpeech},
+		{false, "第1页，共2页：第1天，第2天。" + PaginatorMoreSpeech},
+		{false, PaginatorFirstPageSpeech},
+	} {
+		turn := p.Previous
+		if v.next {
+			turn = p.Next
+		}
+		result, err := turn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, _ := result.GetFirstOutputPlainTextSpeech(); text != v.speech {
+			t.Fatalf("turn %d: want %s, got: %s", i, v.speech, text)
+		}
+	}
+}
+
+func TestEmptyPaginatorResponse(t *testing.T) {
+	resp, err := NewPaginator(nil, 3).Response()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := resp.GetFirstResult().GetFirstOutputPlainTextSpeech(); text != PaginatorEmptySpeech ||
+		resp.Paginator != nil || resp.ShouldEnded() {
+		t.Fatalf("want empty list told and no paginator saved, got: %+v", resp)
+	}
+}
+
+func TestHandlerPagination(t *testing.T) {
+	handler := newDialogHandler(t, `{"dialog": {"intents": [{"name": "Forecast", "slots": []}]}}`)
+	handler.Speechlet = NewIntentRouter().Handle("Forecast",
+		func(re *RequestEnvelope, req *IntentRequest) (*Response, *Context, error) {
+			resp, err := NewPaginator(makePageItems(3), 2).Response()
+			return resp, nil, err
+		})
+	// no list to read yet
+	respEn := intentCall(t, handler, slu.NewIntent(NextIntent))
+	if got := envelopeSpeech(respEn); got != PaginatorNoListSpeech || respEn.Status.Code != ApiSuccess {
+		t.Fatalf("want %q, got %q (%+v)", PaginatorNoListSpeech, got, respEn.Status)
+	}
+	for i, c := range []struct {
+		intent, want string
+	}{
+		{"Forecast", "第1页，共2页：第1天，第2天。" + PaginatorMoreSpeech},
+		{NextIntent, "第2页，共2页：第3天"},
+		{NextIntent, PaginatorLastPageSpeech},
+		{PreviousIntent, "第1页，共2页：第1天，第2天。" + PaginatorMoreSpeech},
+		{PreviousIntent, PaginatorFirstPageSpeech},
+	} {
+		respEn := intentCall(t, handler, slu.NewIntent(c.intent))
+		if got := envelopeSpeech(respEn); got != c.want {
+			t.Fatalf("turn %d %s: want %q, got %q", i, c.intent, c.want, got)
+		}
+	}
+}
+
+func TestHandlerPaginationOtherDialog(t *testing.T) {
+	handler := newDialogHandler(t, `{"dialog": {"intents": [
+	  {"name": "Forecast", "slots": []},
+	  {"name": "Trip", "slots": [{"name": "toCity", "type": "CITY",
+	   "elicitationRequired": true, "prompts": {"elicitation": "Elicit.toCity"}}]}]},
+	  "prompts": [{"id": "Elicit.toCity",
+	   "variations": [{"type": "PlainText", "value": ["Where to?"]}]}]}`)
+	handler.Speechlet = NewIntentRouter().Handle("Forecast",
+		func(re *RequestEnvelope, req *IntentRequest) (*Response, *Context, error) {
+			resp, err := NewPaginator(makePageItems(3), 2).Response()
+			return resp, nil, err
+		}).
+		OnStarted("Trip", delegateHandler).
+		OnInProgress("Trip", delegateHandler)
+	intentCall(t, handler, slu.NewIntent("Forecast"))
+	if got := envelopeSpeech(intentCall(t, handler, slu.NewIntent("Trip"))); got != "Where to?" {
+		t.Fatalf("want the trip dialog started, got %q", got)
+	}
+	// the forecast is not paged by the trip dialog
+	respEn := intentCall(t, handler, slu.NewIntent(NextIntent))
+	if got := envelopeSpeech(respEn); got != PaginatorNoListSpeech {
+		t.Fatalf("want %q, got %q", PaginatorNoListSpeech, got)
+	}
+}
@@ -0,0 +1,93 @@
+package speechlet
+
+import (
+	"encoding/json"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+// Reprompt is what the device says, and optionally shows, when the user
+// stays silent after a response which keeps the session open.
+type Reprompt struct {
+	OutputSpeech *SpeechItems     `json:"outputSpeech,omitempty"`
+	Card         ui.CardInterface `json:"card,omitempty"`
+}
+
+func NewReprompt(items ...*ui.SpeechItem) *Reprompt {
+	return &Reprompt{OutputSpeech: NewSpeechItems(items...)}
+}
+
+func NewPlainTextReprompt(text string) *Reprompt {
+	return NewReprompt(ui.NewPlainTextSpeechItem(text))
+}
+
+func (rp *Reprompt) WithCard(card ui.CardInterface) *Reprompt {
+	rp.Card = card
+	return rp
+}
+
+func (rp *Reprompt) GetOutputSpeech() *SpeechItems {
+	if rp == nil {
+		return nil
+	}
+	return rp.OutputSpeech
+}
+
+func (rp *Reprompt) GetCard() ui.CardInterface {
+	if rp == nil {
+		return nil
+	}
+	return rp.Card
+}
+
+// UnmarshalJSON decodes the card into the type registered for it.
+func (rp *Reprompt) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		OutputSpeech *SpeechItems    `json:"outputSpeech,omitempty"`
+		Card         json.RawMessage `json:"card,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	rp.OutputSpeech, rp.Card = raw.OutputSpeech, nil
+	if len(raw.Card) == 0 || string(raw.Card) == "null" {
+		return nil
+	}
+	card, err := ui.UnmarshalCard(raw.Card)
+	if err != nil {
+		return err
+	}
+	rp.Card = card
+	return nil
+}
+
+// NewAskResponseWithReprompt asks the user and says reprompt if the user
+// stays silent.
+func NewAskResponseWithReprompt(ask, reprompt string) *Response {
+	return NewAskResponse(ask).WithReprompt(NewPlainTextReprompt(reprompt))
+}
+
+func (resp *Response) WithReprompt(rp *Reprompt) *Response {
+	resp.Reprompt = rp
+	return resp
+}
+
+func (resp *Response) GetReprompt() *Reprompt {
+	if resp == nil {
+		return nil
+	}
+	return resp.Reprompt
+}
+
+// makeRepromptFromResult turns the speech and the card of a result into a
+// reprompt.
+func makeRepromptFromResult(result *Result) *Reprompt {
+	if result == nil || result.OutputSpeech == nil {
+		return nil
+	}
+	rp := &Reprompt{OutputSpeech: result.OutputSpeech}
+	if dd := result.GetDisplayDirective(); dd != nil {
+		rp.Card = dd.GetCard()
+	}
+	return rp
+}
@@ -0,0 +1,60 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+func TestAskResponseWithReprompt(t *testing.T) {
+	resp := NewAskResponseWithReprompt("你想去哪里?", "请告诉我你想去的城市")
+	resp.Reprompt.WithCard(ui.NewTextCard("去哪里", ""))
+	respEn := NewResponseEnvelope().WithResults(resp.Results...).
+		WithReprompt(resp.GetReprompt())
+	bytes, _ := json.Marshal(respEn)
+	var raw ResponseEnvelopeRaw
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		t.Fatal(err)
+	}
+	items := raw.Reprompt.GetOutputSpeech().Items
+	if len(items) != 1 || items[0].GetSource() != "请告诉我你想去的城市" {
+		t.Fatalf("want reprompt speech, got: %s", bytes)
+	}
+	if card, ok := raw.Reprompt.GetCard().(*ui.TextCard); !ok || card.Title != "去哪里" {
+		t.Fatalf("want reprompt text card, got: %s", bytes)
+	}
+}
+
+func TestDelegateReprompt(t *testing.T) {
+	dm, err := getDialogModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &RequestHandler{}
+	// toCity has reprompt variations
+	intent := slu.NewIntentFromModel(dm, IntentPlanMyTrip)
+	intent.SetSlot(slu.NewSlot(SlotTravelDate).WithStringValue("2018-06-21"))
+	resp, err := handler.handleDelegateDirective(intent, NewDialogStateMachine(), dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp := resp.GetReprompt()
+	if rp.GetOutputSpeech().Items[0].GetSource() != "Sorry, which city are you traveling to?" {
+		t.Fatalf("want toCity reprompt, got: %+v", rp.GetOutputSpeech())
+	}
+	if card, ok := rp.GetCard().(*ui.TextCard); !ok || card.Title != "Where to?" {
+		t.Fatalf("want toCity reprompt card, got: %#v", rp.GetCard())
+	}
+	// travelDate has none, its prompt is said again
+	intent = slu.NewIntentFromModel(dm, IntentPlanMyTrip)
+	resp, err = handler.handleDelegateDirective(intent, NewDialogStateMachine(), dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := resp.GetFirstResult().GetFirstOutputPlainTextSpeech()
+	if got := resp.GetReprompt().GetOutputSpeech().Items[0].GetSource(); got != want {
+		t.Fatalf("want reprompt %s, got: %s", want, got)
+	}
+}
@@ -133,6 +133,8 @@ type IntentRequest struct {
 	CoreRequest
 	Intent      *slu.Intent     `json:"intent"`
 	DialogState slu.DialogState `json:"dialogState,omitempty"`
+	// Suggestion is the suggestion tapped by the user, if any.
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
 }
 
 func NewIntentRequest(reqId, ts string, intent *slu.Intent) *IntentRequest {
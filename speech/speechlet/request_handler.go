@@ -28,6 +28,24 @@ type RequestHandler struct {
 
 	SlotHandler         reflect.Value
 	DialogModelCallback DialogModelCallback
+
+	// MaxReprompts is how many times in a row a slot may be elicited again
+	// before the session is ended with EXCEEDED_MAX_REPROMPTS, 0 means no
+	// limit. The count starts again when another slot is asked.
+	MaxReprompts int
+	// EmotionPolicy gives an emotion to the results which have none.
+	EmotionPolicy EmotionPolicy
+	// PostProcessors change the responses in turn before they are sent, e.g.
+	// CapabilityAdapter fits them to the device.
+	PostProcessors []ResponsePostProcessor
+	// SessionStore keeps the sessions, they are kept in redis when nil.
+	SessionStore SessionStore
+	// Recorder records the requests with their responses and sessions, e.g.
+	// a FileRecorder whose records are replayed against later builds.
+	Recorder TrafficRecorder
+	// FallbackPolicy answers the user when the speechlet fails or has nothing
+	// to say, see NewFallbackPolicy.
+	FallbackPolicy *FallbackPolicy
 }
 
 type DialogModelCallback interface {
@@ -35,8 +53,15 @@ type DialogModelCallback interface {
 }
 
 func (rh *RequestHandler) HandleCall(reqBytes []byte) ([]byte, error) {
+	if rh.Recorder != nil {
+		return rh.recordCall(reqBytes)
+	}
+	return rh.handleCall(reqBytes)
+}
+
+func (rh *RequestHandler) handleCall(reqBytes []byte) ([]byte, error) {
 	start := time.Now()
-	reqEn, err := makeRequestEnvelope(reqBytes)
+	reqEn, err := ParseRequestEnvelope(reqBytes)
 	if err != nil {
 		log.Printf("ERROR] reqBytes: %s, error: %s", string(reqBytes), err)
 		return nil, err
@@ -55,7 +80,7 @@ func (rh *RequestHandler) HandleCall(reqBytes []byte) ([]byte, error) {
 		}
 	}
 	// dispatch and handle request to get response
-	resp, ctx, err := rh.dispatchCall(reqEn)
+	resp, ctx, err := rh.recoverDispatchCall(reqEn)
 	// verify response
 	//session := req.Session
 	//for _, v := range rh.responseVerifiers {
@@ -66,24 +91,22 @@ func (rh *RequestHandler) HandleCall(reqBytes []byte) ([]byte, error) {
 	//		return nil, errors.New(eString)
 	//	}
 	//}
-	var status *Status
-	if err == nil {
-		status = NewGoodStatus()
-	} else {
-		if err == ErrServiceMismatched {
-			status = NewMismatchStatus(err.Error())
-		} else {
-			status = NewInternalErrStatus(err.Error())
-		}
-		log.Printf("Warning] Request: %s, error: %s", reqEn.Request.GetRequestId(), err)
-	}
-	// make results
+	resp, status := rh.applyFallbackPolicy(reqEn, resp, err)
+	// make results, the post processors may replace them
 	var results []*Result
 	if resp != nil {
+		rh.applyEmotionPolicy(reqEn, resp.Results)
+		for _, v := range rh.PostProcessors {
+			v.PostProcess(reqEn, resp)
+		}
 		results = resp.Results
 	}
+	ctx.applyDefaultLifespan()
 	// make RequestEnvelope
 	respEn := NewResponseEnvelope().WithStatus(status).WithContext(ctx).WithResults(results...)
+	if !resp.ShouldEnded() {
+		respEn.WithReprompt(resp.GetReprompt())
+	}
 	// serialize response
 	respBytes, err := json.MarshalIndent(respEn, "", "  ")
 	if err != nil {
@@ -98,13 +121,15 @@ func (rh *RequestHandler) dispatchCall(reqEn *RequestEnvelope) (
 	if reqEn == nil || reqEn.Context == nil {
 		return nil, nil, errors.New("RequestEnvelope or it's Context is nil")
 	}
+	// drop the context parameters which have expired
+	reqEn.Context.ExpireParameters()
 	userId, appId := reqEn.Context.GetUserId(), reqEn.Context.GetAppId()
 	deviceId, skillId := reqEn.Context.GetDeviceId(), reqEn.Context.GetSkillId()
 	if appId == "" || deviceId == "" || skillId == "" {
 		return nil, nil, errors.New(fmt.Sprintf("AppId[%s], DeviceId[%s], SkillId[%s]"+
 			" not allowed empty", appId, deviceId, skillId))
 	}
-	session, err := FetchSessionFromHistory(userId, appId, deviceId, skillId)
+	session, err := rh.fetchSession(userId, appId, deviceId, skillId)
 	if err != nil {
 		return nil, nil, errors.New("fetch session failed: " + err.Error())
 	}
@@ -127,7 +152,7 @@ func (rh *RequestHandler) dispatchCall(reqEn *RequestEnvelope) (
 	if session.New {
 		err = rh.Speechlet.OnSessionStarted(reqEn)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, fromSpeechlet(err)
 		}
 	}
 	//
@@ -137,16 +162,91 @@ func (rh *RequestHandler) dispatchCall(reqEn *RequestEnvelope) (
 	case SessionEndedRequestType:
 		err = rh.Speechlet.OnSessionEnded(reqEn)
 	case LaunchRequestType:
-		resp, err = rh.Speechlet.OnLaunch(reqEn)
+		resp, err = rh.onLaunch(reqEn)
 	case IntentRequestType:
 		resp, ctx, err = rh.handleIntentRequest(reqEn, session, dm)
 	case IntentsRequestType:
 		// this stage, only call OnIntent, slots info are handled in dst
-		resp, ctx, err = rh.Speechlet.OnIntent(reqEn)
+		resp, ctx, err = rh.onIntent(reqEn)
+	case PlaybackStartedRequestType, PlaybackNearlyFinishedRequestType,
+		PlaybackFinishedRequestType, PlaybackFailedRequestType:
+		resp, err = rh.handleAudioPlayerRequest(reqEn)
+	case TimeoutRequestType:
+		resp, ctx, err = rh.handleTimeoutRequest(reqEn, session, dm)
 	}
 	return resp, ctx, err
 }
 
+func (rh *RequestHandler) handleTimeoutRequest(reqEn *RequestEnvelope, session *Session,
+	dm *model.DialogModel) (*Response, *Context, error) {
+	req, ok := reqEn.Request.(*TimeoutRequest)
+	if !ok {
+		return nil, nil, errors.New(fmt.Sprintf("assert request[%+v] to "+
+			"TimeoutRequest failed, type: %T", reqEn.Request, reqEn.Request))
+	}
+	if ts, ok := rh.Speechlet.(TimeoutSpeechlet); ok {
+		resp, err := ts.OnTimeout(reqEn)
+		if resp != nil || err != nil {
+			return resp, nil, err
+		}
+	}
+	switch req.Action {
+	case TimeoutEndSession:
+		return rh.endSession(reqEn, session, TIMED_OUT)
+	case TimeoutReprompt:
+		dsm := session.GetDialogStateMachine()
+		if !dsm.Active() || dsm.LastPromptId == "" {
+			// nothing to ask again, the user is gone
+			log.Printf("INFO] Request[%s] timeout without dialog to reprompt",
+				req.GetRequestId())
+			return rh.endSession(reqEn, session, TIMED_OUT)
+		}
+		// the confirmations are counted by prompt id
+		if n := dsm.RecordPrompt(dsm.LastSlot, dsm.LastPromptId); rh.MaxReprompts > 0 &&
+			n-1 > rh.MaxReprompts {
+			log.Printf("INFO] Request[%s] exceeded max reprompts", req.GetRequestId())
+			return rh.endSession(reqEn, session, EXCEEDED_MAX_REPROMPTS)
+		}
+		if err := rh.saveSession(session); err != nil {
+			log.Printf("Warning] saveSession[%s] error: %s", req.GetRequestId(), err)
+		}
+		prompt := dm.GetRandomPrompt(dsm.LastPromptId)
+		result := makeResultFromPrompt(prompt)
+		if prompt.HasReprompts() {
+			result = makeResultFromVariations(prompt.Reprompts)
+		}
+		resp := NewResponse().WithResults(result).WithShouldEndSession(false)
+		intent := session.GetUpdatedIntent(dsm.IntentName)
+		if intent == nil {
+			intent = slu.NewIntent(dsm.IntentName)
+		}
+		resolveResponse(intent, resp)
+		return resp, nil, nil
+	}
+	log.Printf("INFO] Request[%s] timeout action %s left to the device", req.GetRequestId(),
+		req.Action)
+	return nil, nil, nil
+}
+
+func (rh *RequestHandler) handleAudioPlayerRequest(reqEn *RequestEnvelope) (*Response, error) {
+	aps, ok := rh.Speechlet.(AudioPlayerSpeechlet)
+	if !ok {
+		log.Printf("Warning] Speechlet %T does not handle request type: %s", rh.Speechlet,
+			reqEn.Request.GetType())
+		return nil, nil
+	}
+	switch reqEn.Request.GetType() {
+	case PlaybackStartedRequestType:
+		return aps.OnPlaybackStarted(reqEn)
+	case PlaybackNearlyFinishedRequestType:
+		return aps.OnPlaybackNearlyFinished(reqEn)
+	case PlaybackFinishedRequestType:
+		return aps.OnPlaybackFinished(reqEn)
+	default:
+		return aps.OnPlaybackFailed(reqEn)
+	}
+}
+
 func (rh *RequestHandler) handleIntentRequest(reqEn *RequestEnvelope,
 	session *Session, dm *model.DialogModel) (resp *Response, ctx *Context, err error) {
 	// pre handle request
@@ -155,6 +255,38 @@ func (rh *RequestHandler) handleIntentRequest(reqEn *RequestEnvelope,
 		return nil, nil, errors.New(fmt.Sprintf("assert request[%+v] to "+
 			"IntentRequest failed, type: %T", reqEn.Request, reqEn.Request))
 	}
+	// the intent of a tapped suggestion bypasses NLU
+	req.applySuggestion()
+	dsm := session.GetDialogStateMachine()
+	switch req.IntentName() {
+	case UndoIntent, StartOverIntent:
+		if dsm.Active() {
+			return rh.handleDialogNavigation(req, session, dm)
+		}
+		if dm.GetIntent(req.IntentName()) == nil {
+			log.Printf("INFO] Request[%s] %s without dialog in progress",
+				req.GetRequestId(), req.IntentName())
+			return NewAskResponse(NoDialogSpeech), nil, nil
+		}
+	case NextIntent, PreviousIntent:
+		if p := session.GetPaginator(); p != nil {
+			return rh.handlePagination(req, session, p)
+		}
+		if dm.GetIntent(req.IntentName()) == nil {
+			log.Printf("INFO] Request[%s] %s without list to read", req.GetRequestId(),
+				req.IntentName())
+			return NewAskResponse(PaginatorNoListSpeech), nil, nil
+		}
+	}
+	if isBuiltinIntent(req.IntentName()) && dm.GetIntent(req.IntentName()) == nil {
+		return rh.handleBuiltinIntent(reqEn, req, session, dm)
+	}
+	// the list read in another dialog is not paged by this one
+	if dsm.IntentName != req.IntentName() {
+		session.ClearPaginator()
+	}
+	// the intent before the turn tells the slots shared to the context before
+	prev := session.GetUpdatedIntent(req.IntentName()).Clone()
 	var ask string
 	if ask, err = rh.preHandleIntentRequest(req, session, dm); err != nil {
 		return nil, nil, err
@@ -167,7 +299,7 @@ func (rh *RequestHandler) handleIntentRequest(reqEn *RequestEnvelope,
 	bytes, _ := json.MarshalIndent(reqEn, "", "  ")
 	log.Printf("OnIntent RequestEnvelope[%s]: %s", req.GetRequestId(), string(bytes))
 	// OnIntent
-	resp, ctx, err = rh.Speechlet.OnIntent(reqEn)
+	resp, ctx, err = rh.onIntent(reqEn)
 	if resp == nil || err != nil {
 		return nil, nil, err
 	}
@@ -180,8 +312,14 @@ func (rh *RequestHandler) handleIntentRequest(reqEn *RequestEnvelope,
 	//
 	if resp.HasDirectives() {
 		log.Printf("INFO] Request[%s] response has directives", req.GetRequestId())
-		resp, err = rh.handleDirectiveResponse(req, resp, dm)
+		resp, err = rh.handleDirectiveResponse(req, resp, dsm, dm)
+		if err == ErrExceededMaxReprompts {
+			log.Printf("INFO] Request[%s] exceeded max reprompts", req.GetRequestId())
+			return rh.endSession(reqEn, session, EXCEEDED_MAX_REPROMPTS)
+		}
 		session.WithUpdatedIntent(req.Intent)
+	} else if req.DialogState == slu.COMPLETED {
+		dsm.ResetAttempts()
 	} /* else {
 		if resp.ShouldEnded() {
 			session.ClearAllIntents()
@@ -193,43 +331,143 @@ func (rh *RequestHandler) handleIntentRequest(reqEn *RequestEnvelope,
 	// try to resolve response
 	resolveResponse(req.Intent, resp)
 
+	session.GetCarryOverSlots().Remember(req.Intent, dm)
+	if resp.Paginator != nil {
+		session.WithPaginator(resp.Paginator)
+	}
 	if resp.ShouldEnded() {
 		session.ClearAllIntents()
+		session.ClearDialogState()
+		session.ClearPaginator()
 	} else {
 		session.MergeIntent(req.Intent)
 	}
 
 	// push session to cache between multiply servers
-	if err := PushSessionToCache(session); err != nil {
-		log.Printf("Warning] PushSessionToCache[%s] error: %s", req.GetRequestId(), err)
+	if err := rh.saveSession(session); err != nil {
+		log.Printf("Warning] saveSession[%s] error: %s", req.GetRequestId(), err)
 	}
 	ssBytes, _ := json.MarshalIndent(session, "", "  ")
 	log.Printf("push request[%s] session to cache: %s", req.GetRequestId(), string(ssBytes))
 	// share slots information to context
-	ctx = rh.shareSlotsToContext(req.Intent, ctx, dm)
+	ctx = rh.shareSlotsToContext(req.Intent, ctx, reqEn.Context, prev, dm)
 	ctx.ClearSystemInfo()
 	//ssBytes, _ = json.MarshalIndent(ctx, "", "  ")
 	//log.Printf("[%s] context to be shared: %s", req.GetRequestId(), string(ssBytes))
 	return resp, ctx, err
 }
 
+// handleBuiltinIntent passes the built-in intent missing from the dialog
+// model to the speechlet, e.g. to the default handlers of an IntentRouter.
+func (rh *RequestHandler) handleBuiltinIntent(reqEn *RequestEnvelope, req *IntentRequest,
+	session *Session, dm *model.DialogModel) (*Response, *Context, error) {
+	resp, ctx, err := rh.onIntent(reqEn)
+	if resp == nil || err != nil {
+		return nil, nil, err
+	}
+	resolveResponse(req.Intent, resp)
+	if resp.Paginator != nil {
+		session.WithPaginator(resp.Paginator)
+	}
+	if resp.ShouldEnded() {
+		session.ClearAllIntents()
+		session.ClearDialogState()
+		session.ClearPaginator()
+	}
+	if err := rh.saveSession(session); err != nil {
+		log.Printf("Warning] saveSession[%s] error: %s", req.GetRequestId(), err)
+	}
+	ctx = rh.shareSlotsToContext(req.Intent, ctx, reqEn.Context, nil, dm)
+	ctx.ClearSystemInfo()
+	return resp, ctx, nil
+}
+
+// handleDialogNavigation moves the dialog in progress one turn back or to its
+// beginning, and asks the user again.
+func (rh *RequestHandler) handleDialogNavigation(req *IntentRequest, session *Session,
+	dm *model.DialogModel) (*Response, *Context, error) {
+	dsm := session.GetDialogStateMachine()
+	intent := slu.NewIntentFromModel(dm, dsm.IntentName)
+	if intent == nil {
+		session.ClearDialogState()
+		return nil, nil, errors.New("NewIntentFromModel failed, intent name mismatched")
+	}
+	var prev *slu.Intent
+	if req.IntentName() == UndoIntent {
+		prev = dsm.Undo()
+	}
+	if prev == nil {
+		dsm.StartOver()
+	} else {
+		intent.Merge(prev)
+		intent.WithStatus(prev.ConfirmationStatus)
+	}
+	log.Printf("INFO] Request[%s] %s in dialog of %s", req.GetRequestId(),
+		req.IntentName(), intent.Name)
+	session.WithUpdatedIntent(intent)
+	req.Intent = intent
+	req.DialogState = slu.IN_PROGRESS
+	resp, err := rh.handleDelegateDirective(intent, dsm, dm)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rh.saveSession(session); err != nil {
+		log.Printf("Warning] saveSession[%s] error: %s", req.GetRequestId(), err)
+	}
+	ctx := rh.shareSlotsToContext(intent, nil, nil, nil, dm)
+	return resp, ctx, nil
+}
+
+// endSession ends the session on behalf of the skill: the dialog of the
+// session is dropped and the speechlet is notified with the given reason.
+func (rh *RequestHandler) endSession(reqEn *RequestEnvelope, session *Session,
+	reason Reason) (*Response, *Context, error) {
+	session.ClearAllIntents()
+	session.ClearDialogState()
+	session.ClearPaginator()
+	if err := rh.saveSession(session); err != nil {
+		log.Printf("Warning] saveSession[%s] error: %s",
+			reqEn.Request.GetRequestId(), err)
+	}
+	endReq := NewSessionEndedRequest(reqEn.Request.GetRequestId(),
+		reqEn.Request.GetTimestamp(), reason, nil)
+	endEn := NewRequestEnvelope().WithContext(reqEn.Context).WithRequest(endReq)
+	if err := rh.Speechlet.OnSessionEnded(endEn); err != nil {
+		return nil, nil, err
+	}
+	return NewResponse().WithShouldEndSession(true), nil, nil
+}
+
 func resolveResponse(intent *slu.Intent, resp *Response) {
-	if resp == nil || len(resp.Results) == 0 {
+	if resp == nil || intent == nil {
 		return
 	}
 	var paramMap map[string]string = make(map[string]string)
+	// values resolved in SSML are escaped to keep it well-formed
+	var ssmlParamMap map[string]string = make(map[string]string)
 	for _, v := range intent.Slots {
 		paramMap[v.Name] = v.GetStringOrgin()
-
+		ssmlParamMap[v.Name] = ui.EscapeSSML(paramMap[v.Name])
 	}
 	for _, v := range resp.Results {
 		_tryResolveParams(&v.Hint, paramMap)
-		if v.OutputSpeech != nil {
-			for _, speechItem := range v.OutputSpeech.Items {
-				if speechItem.Type == ui.PlainTextType {
-					_tryResolveParams(&speechItem.Source, paramMap)
-				}
-			}
+		resolveSpeechItems(v.OutputSpeech, paramMap, ssmlParamMap)
+	}
+	if resp.Reprompt != nil {
+		resolveSpeechItems(resp.Reprompt.OutputSpeech, paramMap, ssmlParamMap)
+	}
+}
+
+func resolveSpeechItems(items *SpeechItems, paramMap, ssmlParamMap map[string]string) {
+	if items == nil {
+		return
+	}
+	for _, speechItem := range items.Items {
+		switch speechItem.Type {
+		case ui.PlainTextType:
+			_tryResolveParams(&speechItem.Source, paramMap)
+		case ui.SSMLType:
+			_tryResolveParams(&speechItem.Source, ssmlParamMap)
 		}
 	}
 }
@@ -295,8 +533,13 @@ func _tryResolveParams(unresolved *string, paramMap map[string]string) bool {
 	return true
 }
 
+// shareSlotsToContext shares the slots of the intent to the context. The
+// slots with a share lifespan get a new one only when their value is new:
+// the ones unchanged since the request context reqCtx keep the lifespan
+// counted so far, and the ones which have expired from it and are unchanged
+// since prev, the intent before the turn, are not shared again.
 func (rh *RequestHandler) shareSlotsToContext(intent *slu.Intent, ctx *Context,
-	dm *model.DialogModel) *Context {
+	reqCtx *Context, prev *slu.Intent, dm *model.DialogModel) *Context {
 	if ctx == nil {
 		ctx = NewContext()
 	}
@@ -308,10 +551,33 @@ func (rh *RequestHandler) shareSlotsToContext(intent *slu.Intent, ctx *Context,
 		if modslot != nil && modslot.ConcealRequired {
 			continue
 		}
-
-		if v.GetValue() != nil {
+		if v.GetValue() == nil {
+			continue
+		}
+		if modslot == nil || modslot.ShareLifespan == nil {
+			ctx.WithParameter(v.Name, v.Value)
+			continue
+		}
+		// inherited slots keep the lifespan they were shared with
+		if v.IsInherited() && ctx.GetParameterLifespan(v.Name) != nil {
 			ctx.WithParameter(v.Name, v.Value)
+			continue
 		}
+		if !v.IsInherited() {
+			old := reqCtx.getParameter(v.Name)
+			if ls := reqCtx.GetParameterLifespan(v.Name); ls != nil &&
+				sameValue(old, v.Value) {
+				ctx.WithParameter(v.Name, v.Value)
+				ctx.setParameterLifespan(v.Name, ls)
+				continue
+			}
+			if old == nil && sameValue(prev.GetSlot(v.Name).GetValue(), v.Value) {
+				continue
+			}
+		}
+		ctx.WithParameter(v.Name, v.Value)
+		ctx.SetParameterLifespan(v.Name, modslot.ShareLifespan.Turns,
+			modslot.ShareLifespan.LifespanInMs)
 	}
 	return ctx
 }
@@ -328,11 +594,19 @@ func (rh *RequestHandler) preHandleIntentRequest(req *IntentRequest,
 	intent.WithSubName(req.SubIntentName())
 	// 2. fetch history slot values from session
 	intent.Merge(session.GetUpdatedIntent(intentName))
+	dsm := session.GetDialogStateMachine()
+	dsm.Begin(intentName)
+	// the intent before the turn is pushed once the turn is applied
+	before := intent.Clone()
 	if intent.Started() {
 		req.DialogState = slu.STARTED
 	} else {
 		req.DialogState = slu.IN_PROGRESS
 	}
+	// inherit the slots shared with the previous intents
+	cos := session.GetCarryOverSlots()
+	cos.NextTurn()
+	cos.Inherit(intent, dm)
 	// debug log
 	//bytes, _ := json.MarshalIndent(intent, "", "  ")
 	//log.Printf("intent merged session history: %s", string(bytes))
@@ -366,6 +640,7 @@ func (rh *RequestHandler) preHandleIntentRequest(req *IntentRequest,
 			return ask, nil
 		}
 	}
+	dsm.Push(before)
 
 	// 5. make requestEnvelope with new intent
 	req.Intent = intent
@@ -378,7 +653,7 @@ func (rh *RequestHandler) preHandleIntentRequest(req *IntentRequest,
 }
 
 func (rh *RequestHandler) handleDirectiveResponse(req *IntentRequest,
-	resp *Response, dm *model.DialogModel) (*Response, error) {
+	resp *Response, dsm *DialogStateMachine, dm *model.DialogModel) (*Response, error) {
 	var err error
 	for _, v := range resp.GetDirectives() {
 		switch v.GetType() {
@@ -387,29 +662,42 @@ func (rh *RequestHandler) handleDirectiveResponse(req *IntentRequest,
 		case directives.DelegateType:
 			updatedIntent := v.GetUpdatedIntent()
 			req.Intent.Merge(updatedIntent)
-			resp, err = rh.handleDelegateDirective(req.Intent, dm)
+			resp, err = rh.handleDelegateDirective(req.Intent, dsm, dm)
 		}
 	}
 	return resp, err
 }
 
 func (rh *RequestHandler) handleDelegateDirective(intent *slu.Intent,
-	dm *model.DialogModel) (*Response, error) {
+	dsm *DialogStateMachine, dm *model.DialogModel) (*Response, error) {
 	var result *Result = nil
+	var reprompt *Reprompt = nil
 	mi := dm.GetIntent(intent.Name)
 	for _, v := range mi.Slots {
 		if v.NeedElicit() && intent.CanElicit(v.Name) {
-			result = makeResultFromPrompt(dm.GetSlotElicit(intent.Name, v.Name))
+			prompt := dm.GetSlotElicit(intent.Name, v.Name)
+			dsm.RecordPrompt(v.Name, prompt.GetID())
+			if rh.MaxReprompts > 0 && dsm.Reprompts(v.Name) > rh.MaxReprompts {
+				return nil, ErrExceededMaxReprompts
+			}
+			result, reprompt = makeResultFromPrompt(prompt), makeRepromptFromPrompt(prompt)
 			break
 		}
 		if v.NeedConfirm() && intent.CanConfirm(v.Name) {
-			result = makeResultFromPrompt(dm.GetSlotConfirmation(intent.Name, v.Name))
+			prompt := dm.GetSlotConfirmation(intent.Name, v.Name)
+			if n := dsm.RecordPrompt("", prompt.GetID()); rh.MaxReprompts > 0 &&
+				n-1 > rh.MaxReprompts {
+				return nil, ErrExceededMaxReprompts
+			}
+			result, reprompt = makeResultFromPrompt(prompt), makeRepromptFromPrompt(prompt)
 			break
 		}
 	}
 	if result == nil {
 		if mi.NeedResult() {
-			result = makeResultFromPrompt(dm.GetIntentResult(intent.Name))
+			prompt := dm.GetIntentResult(intent.Name)
+			dsm.RecordPrompt("", prompt.GetID())
+			result = makeResultFromPrompt(prompt)
 			resp := NewResponse().WithResults(result).WithShouldEndSession(true)
 			return resp, nil
 		} else {
@@ -418,7 +706,8 @@ func (rh *RequestHandler) handleDelegateDirective(intent *slu.Intent,
 		}
 
 	}
-	resp := NewResponse().WithResults(result).WithShouldEndSession(false)
+	resp := NewResponse().WithResults(result).WithReprompt(reprompt).
+		WithShouldEndSession(false)
 	return resp, nil
 }
 
@@ -439,7 +728,9 @@ func parseRequestType(raw interface{}) (string, error) {
 	return t, nil
 }
 
-func makeRequestEnvelope(reqBytes []byte) (*RequestEnvelope, error) {
+// ParseRequestEnvelope decodes a request envelope, its request into the type
+// given by its "type".
+func ParseRequestEnvelope(reqBytes []byte) (*RequestEnvelope, error) {
 	// deserialize request
 	var initRE struct {
 		Version string      `json:"version"`
@@ -454,38 +745,31 @@ func makeRequestEnvelope(reqBytes []byte) (*RequestEnvelope, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the requests are allocated first, json can not set their embedded
+	// pointer to the unexported speechletRequest
 	var req Request
-	bytes, _ := json.Marshal(initRE.Request)
+	core := CoreRequest{new(speechletRequest)}
 	switch RequestType(typ) {
 	default:
-		return nil, errors.New("request type not found")
+		return nil, errors.New(fmt.Sprintf("request type %s not found", typ))
 	case SessionStartedRequestType:
-		var r SessionStartedRequest
-		if err = json.Unmarshal(bytes, &r); err == nil {
-			req = &r
-		}
+		req = &SessionStartedRequest{CoreRequest: core}
 	case SessionEndedRequestType:
-		var r SessionEndedRequest
-		if err = json.Unmarshal(bytes, &r); err == nil {
-			req = &r
-		}
+		req = &SessionEndedRequest{CoreRequest: core}
 	case LaunchRequestType:
-		var r LaunchRequest
-		if err = json.Unmarshal(bytes, &r); err == nil {
-			req = &r
-		}
+		req = &LaunchRequest{CoreRequest: core}
 	case IntentRequestType:
-		var r IntentRequest
-		if err = json.Unmarshal(bytes, &r); err == nil {
-			req = &r
-		}
+		req = &IntentRequest{CoreRequest: core}
 	case IntentsRequestType:
-		var r IntentsRequest
-		if err = json.Unmarshal(bytes, &r); err == nil {
-			req = &r
-		}
+		req = &IntentsRequest{CoreRequest: core}
+	case PlaybackStartedRequestType, PlaybackNearlyFinishedRequestType,
+		PlaybackFinishedRequestType, PlaybackFailedRequestType:
+		req = &AudioPlayerRequest{CoreRequest: core}
+	case TimeoutRequestType:
+		req = &TimeoutRequest{CoreRequest: core}
 	}
-	if err != nil {
+	bytes, _ := json.Marshal(initRE.Request)
+	if err = json.Unmarshal(bytes, req); err != nil {
 		return nil, err
 	}
 	reqEn := RequestEnvelope{
@@ -501,18 +785,40 @@ func makeResultFromPrompt(prompt *model.Prompt) *Result {
 	if prompt == nil {
 		return nil
 	}
+	return makeResultFromVariations(prompt.Variations)
+}
 
+// makeRepromptFromPrompt makes the reprompt of a prompt from its reprompt
+// variations, or from the prompt itself if it has none.
+func makeRepromptFromPrompt(prompt *model.Prompt) *Reprompt {
+	if prompt.HasReprompts() {
+		return makeRepromptFromResult(makeResultFromVariations(prompt.Reprompts))
+	}
+	return makeRepromptFromResult(makeResultFromPrompt(prompt))
+}
+
+func makeResultFromVariations(variations []*model.Variation) *Result {
 	result := NewResult()
 	var firstText bool = false
-	for _, v := range prompt.Variations {
+	for _, v := range variations {
+		if v == nil || len(v.Value) == 0 {
+			continue
+		}
 		rand.Seed(int64(time.Now().Second()))
 		idx := rand.Intn(len(v.Value))
 		selectedValue := v.Value[idx]
 
+		if v.Emotion != "" {
+			if err := ValidateEmotionCode(EmotionCode(v.Emotion)); err != nil {
+				log.Printf("Warning] %s variation: %s", v.Type, err)
+			} else {
+				result.WithEmotions(NewEmotionWithEmotionCode(EmotionCode(v.Emotion)))
+			}
+		}
 		if ui.SpeechType(v.Type) == ui.PlainTextType {
 			var plainText string
 			if err := json.Unmarshal([]byte(selectedValue), &plainText); err != nil {
-				log.Printf("DisplayDirectiveRaw %+v GetCard error: %s", selectedValue, err)
+				log.Printf("PlainText variation %s unmarshal error: %s", selectedValue, err)
 				continue
 			}
 
@@ -526,13 +832,13 @@ func makeResultFromPrompt(prompt *model.Prompt) *Result {
 		if ui.SpeechType(v.Type) == ui.AudioType {
 			var audio string
 			if err := json.Unmarshal([]byte(selectedValue), &audio); err != nil {
-				log.Printf("DisplayDirectiveRaw %+v GetCard error: %s", selectedValue, err)
+				log.Printf("Audio variation %s unmarshal error: %s", selectedValue, err)
 				continue
 			}
 
 			result.WithOutputAudioSpeech(audio)
 		}
-		/*if DirectiveType(v.Type) == DisplayDirectiveType {
+		if DirectiveType(v.Type) == DisplayDirectiveType {
 			var raw DisplayDirectiveRaw
 			if err := json.Unmarshal([]byte(selectedValue), &raw); err != nil {
 				log.Printf("DisplayDirectiveRaw %+v GetCard error: %s", selectedValue, err)
@@ -541,7 +847,7 @@ func makeResultFromPrompt(prompt *model.Prompt) *Result {
 
 			result.WithDisplayDirective(NewDisplayDirective().
 				WithCard(raw.GetCard()))
-		}*/
+		}
 	}
 	return result
 }
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"reflect"
+	"strings"
 	"testing"
 
 	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
 )
 
 var (
@@ -46,4 +50,238 @@ func TestTryResolveParams(t *testing.T) {
 	paramMap["city"] = "北京"
 	paramMap["date"] = "明天"
 	_tryResolveParams(&unresolved, paramMap)
-}
\ No newline at end of file
+}
+
+func TestResolveResponseSSML(t *testing.T) {
+	intent := slu.NewIntent("SearchOneDay").
+		WithSlot(slu.NewSlot("city").WithValue(slu.NewStringValue("A&B").WithOrigin("A&B")))
+	resp := NewResponse().WithResults(NewResult().
+		WithOutputPlainTextSpeech("{$city}天气").
+		WithOutputSsmlSpeech(ui.SSML().Say("{$city}天气").String()))
+	resolveResponse(intent, resp)
+	items := resp.GetFirstResult().GetOutputSpeech().Items
+	if items[0].GetSource() != "A&B天气" {
+		t.Fatalf("plain text got: %s", items[0].GetSource())
+	}
+	if err := ui.ValidateSSML(items[1].GetSource()); err != nil ||
+		items[1].GetSource() != "<speak>A&amp;B天气</speak>" {
+		t.Fatalf("ssml got: %s, %v", items[1].GetSource(), err)
+	}
+}
+
+// confirmDialog has a dialog eliciting then confirming toCity, its prompts
+// say the value of the slot.
+const confirmDialog = `{
+  "dialog": {"intents": [{"name": "Trip", "slots": [
+    {"name": "toCity", "type": "CITY", "elicitationRequired": true,
+     "confirmationRequired": true, "shareLifespan": {"turns": 2},
+     "prompts": {"elicitation": "Elicit.toCity", "confirmation": "Confirm.toCity"}},
+    {"name": "date", "type": "DATE", "elicitationRequired": true,
+     "prompts": {"elicitation": "Elicit.date"}}]}]},
+  "prompts": [
+    {"id": "Elicit.toCity", "variations": [{"type": "PlainText", "value": ["Where to?"]}]},
+    {"id": "Elicit.date", "variations": [{"type": "PlainText", "value": ["When to {$toCity}?"]}],
+     "reprompts": [{"type": "PlainText", "value": ["Which day to {$toCity}?"]}]},
+    {"id": "Confirm.toCity", "variations": [{"type": "PlainText", "value": ["Go to {$toCity}?"]}]}
+  ]
+}`
+
+// newDialogHandler makes a handler of the dialog whose completed intents are
+// told "done", with the sessions kept in memory.
+func newDialogHandler(t *testing.T, dialog string) *RequestHandler {
+	dm := new(model.DialogModel)
+	if err := json.Unmarshal([]byte(dialog), dm); err != nil {
+		t.Fatal(err)
+	}
+	return &RequestHandler{
+		Speechlet: NewIntentRouter().WithFallback(
+			func(re *RequestEnvelope, req *IntentRequest) (*Response, *Context, error) {
+				return NewTellResponse("done"), nil, nil
+			}).
+			OnStarted("Trip", delegateHandler).
+			OnInProgress("Trip", delegateHandler),
+		DialogModel:  dm,
+		SessionStore: NewMemorySessionStore(),
+	}
+}
+
+// handlerCall sends the request to the handler as the platform does, with
+// the context given if any, and decodes the response envelope.
+func handlerCall(t *testing.T, handler *RequestHandler, req Request,
+	ctx *Context) *ResponseEnvelopeRaw {
+	if ctx == nil {
+		ctx = NewContext()
+	}
+	ctx.WithSystem(NewCtxSystem().WithUser(NewUser("u", "a")).
+		WithDevice(NewDevice("d")).WithSkill(NewSkill("s")))
+	reqBytes, _ := json.Marshal(NewRequestEnvelope().WithContext(ctx).WithRequest(req))
+	respBytes, err := handler.HandleCall(reqBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respEn := new(ResponseEnvelopeRaw)
+	if err := json.Unmarshal(respBytes, respEn); err != nil {
+		t.Fatalf("%s: %s", err, respBytes)
+	}
+	return respEn
+}
+
+func intentCall(t *testing.T, handler *RequestHandler, intent *slu.Intent) *ResponseEnvelopeRaw {
+	return handlerCall(t, handler, NewIntentRequest("req-1", "", intent), nil)
+}
+
+func tripIntent(slots ...string) *slu.Intent {
+	intent := slu.NewIntent("Trip")
+	for i := 0; i+1 < len(slots); i += 2 {
+		intent.WithSlot(slu.NewSlot(slots[i]).
+			WithValue(slu.NewStringValue(slots[i+1]).WithOrigin(slots[i+1])))
+	}
+	return intent
+}
+
+// envelopeSpeech joins the speeches of the results of the envelope.
+func envelopeSpeech(respEn *ResponseEnvelopeRaw) string {
+	var speeches []string
+	for _, v := range respEn.GetResults() {
+		if v.OutputSpeech == nil {
+			continue
+		}
+		for _, item := range v.OutputSpeech.Items {
+			speeches = append(speeches, item.GetSource())
+		}
+	}
+	return strings.Join(speeches, " ")
+}
+
+func TestResolveReprompt(t *testing.T) {
+	intent := slu.NewIntent("SearchOneDay").
+		WithSlot(slu.NewSlot("city").WithValue(slu.NewStringValue("A&B").WithOrigin("A&B")))
+	resp := NewAskResponse("{$city}?").WithReprompt(NewReprompt(
+		ui.NewPlainTextSpeechItem("{$city}天气?"),
+		ui.NewSsmlSpeechItem(ui.SSML().Say("{$city}天气?").String())))
+	resolveResponse(intent, resp)
+	items := resp.GetReprompt().GetOutputSpeech().Items
+	if items[0].GetSource() != "A&B天气?" || items[1].GetSource() != "<speak>A&amp;B天气?</speak>" {
+		t.Fatalf("reprompt got: %s, %s", items[0].GetSource(), items[1].GetSource())
+	}
+}
+
+func TestHandlerResolvesReprompt(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	intent := tripIntent("toCity", "Paris")
+	intent.GetSlot("toCity").WithStatus(slu.CONFIRMED)
+	respEn := intentCall(t, handler, intent)
+	if got := envelopeSpeech(respEn); got != "When to Paris?" {
+		t.Fatalf("want date elicited, got %q", got)
+	}
+	if respEn.Reprompt == nil || respEn.Reprompt.OutputSpeech == nil ||
+		respEn.Reprompt.OutputSpeech.Items[0].GetSource() != "Which day to Paris?" {
+		t.Fatalf("want reprompt resolved, got: %+v", respEn.Reprompt)
+	}
+}
+
+func TestHandlerMaxRepromptsOfConfirmation(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	handler.MaxReprompts = 1
+	// toCity is confirmed again and again as the user neither confirms nor denies
+	for i, want := range []string{"Go to Paris?", "Go to Paris?", ""} {
+		respEn := intentCall(t, handler, tripIntent("toCity", "Paris"))
+		if got := envelopeSpeech(respEn); got != want || respEn.Status.Code != ApiSuccess {
+			t.Fatalf("turn %d: want %q, got %q (%+v)", i, want, got, respEn.Status)
+		}
+	}
+	session, _ := handler.SessionStore.Fetch("u", "a", "d", "s")
+	if session.GetDialogStateMachine().Active() {
+		t.Fatal("want dialog dropped after exceeded max reprompts")
+	}
+}
+
+func TestHandlerUndo(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	// no dialog to undo
+	respEn := intentCall(t, handler, slu.NewIntent(UndoIntent))
+	if got := envelopeSpeech(respEn); got != NoDialogSpeech || respEn.Status.Code != ApiSuccess {
+		t.Fatalf("want %q, got %q (%+v)", NoDialogSpeech, got, respEn.Status)
+	}
+	intentCall(t, handler, tripIntent())
+	intentCall(t, handler, tripIntent("toCity", "Paris"))
+	// undo the answer Paris, toCity is elicited again
+	respEn = intentCall(t, handler, slu.NewIntent(UndoIntent))
+	if got := envelopeSpeech(respEn); got != "Where to?" {
+		t.Fatalf("undo want %q, got %q", "Where to?", got)
+	}
+	respEn = intentCall(t, handler, slu.NewIntent(StartOverIntent))
+	if got := envelopeSpeech(respEn); got != "Where to?" {
+		t.Fatalf("start over want %q, got %q", "Where to?", got)
+	}
+}
+
+// cityChecker is a SlotHandler asking again for the cities it does not know.
+type cityChecker struct{}
+
+func (cityChecker) CheckCity(slot *slu.Slot, intent *slu.Intent) string {
+	if slot.GetStringValue() == "Atlantis" {
+		return "No trip to Atlantis, where else?"
+	}
+	return ""
+}
+
+func TestPreHandleIntentRequestUndoHistory(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	handler.DialogModel.GetSlot("Trip", "toCity").Handler = "CheckCity"
+	handler.SlotHandler = reflect.ValueOf(cityChecker{})
+	session := NewSession("u", "a", "d", "s")
+	history := func() int {
+		return len(session.GetDialogStateMachine().History)
+	}
+	ask, err := handler.preHandleIntentRequest(
+		NewIntentRequest("req-1", "", tripIntent("toCity", "Atlantis")),
+		session, handler.DialogModel)
+	if err != nil || ask == "" || history() != 0 {
+		t.Fatalf("want an ask and no turn pushed, got: %q, %v, %d turns", ask, err, history())
+	}
+	ask, err = handler.preHandleIntentRequest(
+		NewIntentRequest("req-2", "", tripIntent("toCity", "Paris")),
+		session, handler.DialogModel)
+	if err != nil || ask != "" || history() != 1 {
+		t.Fatalf("want the turn pushed, got: %q, %v, %d turns", ask, err, history())
+	}
+}
+
+func TestHandlerBuiltinIntents(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	respEn := intentCall(t, handler, slu.NewIntent(HelpIntent))
+	if got := envelopeSpeech(respEn); got != RouterHelpSpeech || respEn.Status.Code != ApiSuccess {
+		t.Fatalf("want %q, got %q (%+v)", RouterHelpSpeech, got, respEn.Status)
+	}
+	intentCall(t, handler, tripIntent())
+	// stop leaves the dialog in progress
+	respEn = intentCall(t, handler, slu.NewIntent(StopIntent))
+	if got := envelopeSpeech(respEn); got != RouterGoodbyeSpeech || respEn.Status.Code != ApiSuccess {
+		t.Fatalf("want %q, got %q (%+v)", RouterGoodbyeSpeech, got, respEn.Status)
+	}
+	session, _ := handler.SessionStore.Fetch("u", "a", "d", "s")
+	if session.GetDialogStateMachine().Active() {
+		t.Fatal("want dialog dropped after stop")
+	}
+}
+
+// replacingProcessor replaces the results rather than changing them.
+type replacingProcessor struct{}
+
+func (replacingProcessor) PostProcess(reqEn *RequestEnvelope, resp *Response) {
+	resp.Results = []*Result{NewResult().WithOutputPlainTextSpeech("替换")}
+}
+
+func TestHandlerPostProcessorReplacesResults(t *testing.T) {
+	handler := &RequestHandler{
+		Speechlet:      &greetSpeechlet{},
+		DialogModel:    &model.DialogModel{},
+		SessionStore:   NewMemorySessionStore(),
+		PostProcessors: []ResponsePostProcessor{replacingProcessor{}},
+	}
+	respEn := handlerCall(t, handler, NewLaunchRequest("req-1", ""), nil)
+	if got := envelopeSpeech(respEn); got != "替换" {
+		t.Fatalf("want the replaced results, got: %q", got)
+	}
+}
@@ -137,3 +137,18 @@ func TestIntentRequestStatus(t *testing.T) {
 			"model intent: %s", string(intReqBytes), string(miBytes))
 	}
 }
+
+func TestParseRequestEnvelope(t *testing.T) {
+	reqEn, err := ParseRequestEnvelope([]byte(`{"version":"2.0","context":{},` +
+		`"request":{"type":"LaunchRequest","requestId":"rosai.request.test001"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reqEn.Request.(*LaunchRequest); !ok ||
+		reqEn.Request.GetRequestId() != "rosai.request.test001" {
+		t.Fatalf("want launch request, got: %+v", reqEn.Request)
+	}
+	if _, err := ParseRequestEnvelope([]byte(`{"request":{"type":"Unknown"}}`)); err == nil {
+		t.Fatal("unknown request type should fail")
+	}
+}
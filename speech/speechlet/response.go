@@ -10,17 +10,19 @@ import (
 )
 
 type ResponseEnvelope struct {
-	Version string    `json:"version"`
-	Status  *Status   `json:"status"`
-	Context *Context  `json:"context,omitempty"`
-	Results []*Result `json:"results,omitempty"`
+	Version  string    `json:"version"`
+	Status   *Status   `json:"status"`
+	Context  *Context  `json:"context,omitempty"`
+	Results  []*Result `json:"results,omitempty"`
+	Reprompt *Reprompt `json:"reprompt,omitempty"`
 }
 
 type ResponseEnvelopeRaw struct {
-	Version string          `json:"version"`
-	Status  *Status         `json:"status"`
-	Context *Context        `json:"context,omitempty"`
-	Results json.RawMessage `json:"results,omitempty"`
+	Version  string          `json:"version"`
+	Status   *Status         `json:"status"`
+	Context  *Context        `json:"context,omitempty"`
+	Results  json.RawMessage `json:"results,omitempty"`
+	Reprompt *Reprompt       `json:"reprompt,omitempty"`
 }
 
 func (raw *ResponseEnvelopeRaw) GetResults() []*Result {
@@ -29,9 +31,6 @@ func (raw *ResponseEnvelopeRaw) GetResults() []*Result {
 		log.Println(err)
 		return nil
 	}
-	/*var typePre struct {
-		Type string `json:"type"`
-	}*/
 	results := make([]*Result, 0)
 	for _, v := range resultsRaw {
 		item := new(Result)
@@ -40,37 +39,17 @@ func (raw *ResponseEnvelopeRaw) GetResults() []*Result {
 		item.OutputSpeech = v.OutputSpeech
 		item.Script = v.Script
 		item.Data = v.Data
-		/*for _, w := range v.Directives {
-			var dir Directive
-			if err := json.Unmarshal([]byte(w), &typePre); err != nil {
+		item.Timeout = v.Timeout
+		item.Emotions = v.Emotions
+		item.Suggestions = v.Suggestions
+		for _, w := range v.Directives {
+			dir, err := UnmarshalDirective(w)
+			if err != nil {
 				log.Println(err)
 				return nil
 			}
-			switch DirectiveType(typePre.Type) {
-			case DisplayDirectiveType:
-				var dis DisplayDirectiveRaw
-				if err := json.Unmarshal([]byte(w), &dis); err != nil {
-					log.Println(err)
-					return nil
-				}
-				var disp DisplayDirective
-				disp.Type = dis.Type
-				disp.Hint = dis.Hint
-				disp.Suggestions = dis.Suggestions
-				disp.Card = dis.GetCard()
-				dir = &disp
-			case EventDirectiveType:
-				var event EventDirective
-				if err := json.Unmarshal([]byte(w), &event); err != nil {
-					log.Println(err)
-				}
-				dir = &event
-			default:
-				log.Printf("unrecognize directive type: %s", typePre)
-				return nil
-			}
 			item.Directives = append(item.Directives, dir)
-		}*/
+		}
 		results = append(results, item)
 	}
 	return results
@@ -95,6 +74,11 @@ func (respEn *ResponseEnvelope) WithResults(results ...*Result) *ResponseEnvelop
 	return respEn
 }
 
+func (respEn *ResponseEnvelope) WithReprompt(rp *Reprompt) *ResponseEnvelope {
+	respEn.Reprompt = rp
+	return respEn
+}
+
 func NewErrResponseEnvelope(detail string) *ResponseEnvelope {
 	return NewResponseEnvelope().WithStatus(NewInternalErrStatus(detail))
 }
@@ -102,7 +86,10 @@ func NewErrResponseEnvelope(detail string) *ResponseEnvelope {
 type Response struct {
 	Results          []*Result              `json:"results,omitempty"`
 	Directives       []directives.Directive `json:"directives,omitempty"`
+	Reprompt         *Reprompt              `json:"reprompt,omitempty"`
 	ShouldEndSession bool                   `json:"shouldEndSession"`
+	// Paginator is saved in the session to read the next pages.
+	Paginator *Paginator `json:"-"`
 }
 
 func NewAskResponse(ask string) *Response {
@@ -181,8 +168,10 @@ const (
 )
 
 type Timeout struct {
-	TimeInMillseconds int    `json:"timeInMs"`
-	Action            string `json:"action"`
+	TimeInMillseconds int           `json:"timeInMs"`
+	Action            TimeoutAction `json:"action"`
+	// Event is the name of the custom event of a TimeoutEvent action.
+	Event string `json:"event,omitempty"`
 }
 
 type Result struct {
@@ -190,20 +179,25 @@ type Result struct {
 	Hint         string       `json:"hint,omitempty"`
 	OutputSpeech *SpeechItems `json:"outputSpeech,omitempty"`
 	Script       *ScriptItems `json:"script,omitempty"`
-	//Directives   []Directive  `json:"directives,omitempty"`
-	Data interface{} `json:"data,omitempty"`
+	Directives   []Directive  `json:"directives,omitempty"`
+	Data         interface{}  `json:"data,omitempty"`
 
-	Timeout  *Timeout   `json:"timeout,omitempty"`
-	Emotions []*Emotion `json:"emotions,omitempty"`
+	Timeout     *Timeout      `json:"timeout,omitempty"`
+	Emotions    []*Emotion    `json:"emotions,omitempty"`
+	Suggestions []*Suggestion `json:"suggestions,omitempty"`
 }
 
 type ResultRaw struct {
-	FormatType   FormatType   `json:"formatType,omitempty"`
-	Hint         string       `json:"hint,omitempty"`
-	OutputSpeech *SpeechItems `json:"outputSpeech,omitempty"`
-	Script       *ScriptItems `json:"script,omitempty"`
-	//Directives   []json.RawMessage `json:"directives,omitempty"`
-	Data interface{} `json:"data,omitempty"`
+	FormatType   FormatType        `json:"formatType,omitempty"`
+	Hint         string            `json:"hint,omitempty"`
+	OutputSpeech *SpeechItems      `json:"outputSpeech,omitempty"`
+	Script       *ScriptItems      `json:"script,omitempty"`
+	Directives   []json.RawMessage `json:"directives,omitempty"`
+	Data         interface{}       `json:"data,omitempty"`
+
+	Timeout     *Timeout      `json:"timeout,omitempty"`
+	Emotions    []*Emotion    `json:"emotions,omitempty"`
+	Suggestions []*Suggestion `json:"suggestions,omitempty"`
 }
 
 type SpeechItems struct {
@@ -260,6 +254,19 @@ func (si *ScriptItems) AppendH5Items(texts ...string) *ScriptItems {
 	return si
 }
 
+func (r *Result) WithScript(items *ScriptItems) *Result {
+	r.Script = items
+	return r
+}
+
+func (r *Result) WithScriptItems(items ...*ui.ScriptItem) *Result {
+	if r.Script == nil {
+		r.Script = NewScriptItems()
+	}
+	r.Script.AppendItems(items...)
+	return r
+}
+
 func NewResult() *Result {
 	return &Result{}
 }
@@ -337,13 +344,17 @@ func (r *Result) WithOutputAudioSpeech(urls ...string) *Result {
 	return r
 }
 
-/*func (r *Result) GetDirectives() []Directive {
+func (r *Result) GetDirectives() []Directive {
+	if r == nil {
+		return nil
+	}
 	return r.Directives
 }
 
-func (r *Result) WithDisplayDirective(dir *DisplayDirective) *Result {
+// WithDirective replaces the first directive of the same type, or appends it.
+func (r *Result) WithDirective(dir Directive) *Result {
 	for i, v := range r.Directives {
-		if v.GetType() == DisplayDirectiveType {
+		if v.GetType() == dir.GetType() {
 			r.Directives[i] = dir
 			return r
 		}
@@ -352,20 +363,22 @@ func (r *Result) WithDisplayDirective(dir *DisplayDirective) *Result {
 	return r
 }
 
+func (r *Result) AppendDirectives(dirs ...Directive) *Result {
+	r.Directives = append(r.Directives, dirs...)
+	return r
+}
+
+func (r *Result) WithDisplayDirective(dir *DisplayDirective) *Result {
+	return r.WithDirective(dir)
+}
+
 func (r *Result) AppendDisplayDirective(dir *DisplayDirective) *Result {
 	r.Directives = append(r.Directives, dir)
 	return r
 }
 
 func (r *Result) WithEventDirective(dir *EventDirective) *Result {
-	for i, v := range r.Directives {
-		if v.GetType() == EventDirectiveType {
-			r.Directives[i] = dir
-			return r
-		}
-	}
-	r.Directives = append(r.Directives, dir)
-	return r
+	return r.WithDirective(dir)
 }
 
 func (r *Result) GetDisplayDirective() *DisplayDirective {
@@ -392,7 +405,7 @@ func (r *Result) GetEventDirective() *EventDirective {
 		}
 	}
 	return nil
-}*/
+}
 
 // deprecated
 func (r *Result) GetFormatType() FormatType {
@@ -0,0 +1,262 @@
+package speechlet
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/directives"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+// Speeches of the default handlers of the router, {$intent} in
+// RouterUnsupportedSpeech is replaced by the name of the intent.
+var (
+	RouterWelcomeSpeech     = "欢迎使用，请问有什么可以帮您？"
+	RouterHelpSpeech        = "您可以直接告诉我您想做什么"
+	RouterGoodbyeSpeech     = "好的，再见"
+	RouterUnsupportedSpeech = "暂不支持{$intent}，请换个说法试试"
+)
+
+// IntentHandlerFunc handles an intent request routed by an IntentRouter.
+type IntentHandlerFunc func(re *RequestEnvelope, req *IntentRequest) (
+	*Response, *Context, error)
+
+type intentRoute struct {
+	handler IntentHandlerFunc
+	states  map[slu.DialogState]IntentHandlerFunc
+	subs    map[string]*intentRoute
+}
+
+func newIntentRoute() *intentRoute {
+	return &intentRoute{states: make(map[slu.DialogState]IntentHandlerFunc)}
+}
+
+// lookup returns the handler of the route for the dialog state, the one
+// registered for the state first, then the one of the whole intent.
+func (ir *intentRoute) lookup(state slu.DialogState) IntentHandlerFunc {
+	if ir == nil {
+		return nil
+	}
+	if h, ok := ir.states[state]; ok {
+		return h
+	}
+	return ir.handler
+}
+
+// dialogOnly is true for the routes with handlers of some dialog states only,
+// the other states of their dialog are delegated to the dialog model.
+func (ir *intentRoute) dialogOnly() bool {
+	return ir != nil && ir.handler == nil && len(ir.states) > 0
+}
+
+// IntentRouter is a Speechlet dispatching the intent requests to the
+// handlers registered by intent name, sub intent name and dialog state, in
+// place of a switch on the intent name in OnIntent:
+//
+//	router := sp.NewIntentRouter().
+//		Handle("HelloWorldIntent", onHello).
+//		OnCompleted("PlanMyTrip", onTripPlanned)
+//
+// An intent with handlers of some dialog states only delegates its other
+// uncompleted states to the dialog model. ROSAI.HelpIntent, ROSAI.StopIntent and
+// ROSAI.CancelIntent are answered by default, the intents without handler
+// by the fallback.
+type IntentRouter struct {
+	routes         map[string]*intentRoute
+	builtins       map[string]IntentHandlerFunc
+	fallback       IntentHandlerFunc
+	launch         func(re *RequestEnvelope) (*Response, error)
+	sessionStarted func(re *RequestEnvelope) error
+	sessionEnded   func(re *RequestEnvelope) error
+}
+
+func NewIntentRouter() *IntentRouter {
+	router := &IntentRouter{routes: make(map[string]*intentRoute)}
+	router.builtins = map[string]IntentHandlerFunc{
+		HelpIntent:   defaultHelpHandler,
+		StopIntent:   defaultGoodbyeHandler,
+		CancelIntent: defaultGoodbyeHandler,
+	}
+	router.fallback = defaultFallbackHandler
+	return router
+}
+
+func defaultHelpHandler(re *RequestEnvelope, req *IntentRequest) (
+	*Response, *Context, error) {
+	return NewAskResponse(RouterHelpSpeech), nil, nil
+}
+
+func defaultGoodbyeHandler(re *RequestEnvelope, req *IntentRequest) (
+	*Response, *Context, error) {
+	return NewTellResponse(RouterGoodbyeSpeech), nil, nil
+}
+
+func defaultFallbackHandler(re *RequestEnvelope, req *IntentRequest) (
+	*Response, *Context, error) {
+	return NewAskResponse(strings.Replace(RouterUnsupportedSpeech, "{$intent}",
+		req.IntentName(), -1)), nil, nil
+}
+
+func (router *IntentRouter) route(name, subName string) *intentRoute {
+	r, ok := router.routes[name]
+	if !ok {
+		r = newIntentRoute()
+		router.routes[name] = r
+	}
+	if subName == "" {
+		return r
+	}
+	if r.subs == nil {
+		r.subs = make(map[string]*intentRoute)
+	}
+	sub, ok := r.subs[subName]
+	if !ok {
+		sub = newIntentRoute()
+		r.subs[subName] = sub
+	}
+	return sub
+}
+
+// Handle registers the handler of the intent in every dialog state. The
+// name is either an intent name, or an intent name and a sub intent name
+// joined by a slash, PlayMusic/ByArtist.
+func (router *IntentRouter) Handle(name string, fn IntentHandlerFunc) *IntentRouter {
+	intentName, subName := splitIntentName(name)
+	router.route(intentName, subName).handler = fn
+	return router
+}
+
+// OnStarted registers the handler of the first turn of the dialog of the
+// intent, name as in Handle.
+func (router *IntentRouter) OnStarted(name string, fn IntentHandlerFunc) *IntentRouter {
+	return router.onState(name, slu.STARTED, fn)
+}
+
+// OnInProgress registers the handler of the turns of the dialog of the
+// intent collecting its slots, name as in Handle.
+func (router *IntentRouter) OnInProgress(name string, fn IntentHandlerFunc) *IntentRouter {
+	return router.onState(name, slu.IN_PROGRESS, fn)
+}
+
+// OnCompleted registers the handler of the dialog of the intent once all
+// its required slots are filled and confirmed, name as in Handle.
+func (router *IntentRouter) OnCompleted(name string, fn IntentHandlerFunc) *IntentRouter {
+	return router.onState(name, slu.COMPLETED, fn)
+}
+
+func (router *IntentRouter) onState(name string, state slu.DialogState,
+	fn IntentHandlerFunc) *IntentRouter {
+	intentName, subName := splitIntentName(name)
+	router.route(intentName, subName).states[state] = fn
+	return router
+}
+
+func splitIntentName(name string) (string, string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// WithFallback sets the handler of the intents without handler, which are
+// told they are unsupported by default.
+func (router *IntentRouter) WithFallback(fn IntentHandlerFunc) *IntentRouter {
+	router.fallback = fn
+	return router
+}
+
+// WithoutDefaults removes the default handlers of the built-in intents, so
+// that the intents without handler all go to the fallback.
+func (router *IntentRouter) WithoutDefaults() *IntentRouter {
+	router.builtins = make(map[string]IntentHandlerFunc)
+	return router
+}
+
+func (router *IntentRouter) WithLaunch(
+	fn func(re *RequestEnvelope) (*Response, error)) *IntentRouter {
+	router.launch = fn
+	return router
+}
+
+func (router *IntentRouter) WithSessionStarted(
+	fn func(re *RequestEnvelope) error) *IntentRouter {
+	router.sessionStarted = fn
+	return router
+}
+
+func (router *IntentRouter) WithSessionEnded(
+	fn func(re *RequestEnvelope) error) *IntentRouter {
+	router.sessionEnded = fn
+	return router
+}
+
+// Lookup returns the handler of the intent request, nil when it would go to
+// the fallback.
+func (router *IntentRouter) Lookup(req *IntentRequest) IntentHandlerFunc {
+	r, ok := router.routes[req.IntentName()]
+	if !ok {
+		return router.builtins[req.IntentName()]
+	}
+	if sub, ok := r.subs[req.SubIntentName()]; ok {
+		if h := sub.lookup(req.DialogState); h != nil {
+			return h
+		}
+		if sub.dialogOnly() && req.DialogState != slu.COMPLETED {
+			return delegateHandler
+		}
+	}
+	if h := r.lookup(req.DialogState); h != nil {
+		return h
+	}
+	if r.dialogOnly() && req.DialogState != slu.COMPLETED {
+		return delegateHandler
+	}
+	return router.builtins[req.IntentName()]
+}
+
+func delegateHandler(re *RequestEnvelope, req *IntentRequest) (
+	*Response, *Context, error) {
+	return NewDelegateResponse([]directives.Directive{
+		directives.NewDelegateDirective(req.GetIntent())}), nil, nil
+}
+
+func (router *IntentRouter) OnSessionStarted(re *RequestEnvelope) error {
+	if router.sessionStarted == nil {
+		return nil
+	}
+	return router.sessionStarted(re)
+}
+
+func (router *IntentRouter) OnLaunch(re *RequestEnvelope) (*Response, error) {
+	if router.launch == nil {
+		return NewAskResponse(RouterWelcomeSpeech), nil
+	}
+	return router.launch(re)
+}
+
+func (router *IntentRouter) OnIntent(re *RequestEnvelope) (*Response, *Context, error) {
+	req, ok := re.Request.(*IntentRequest)
+	if !ok {
+		return nil, nil, errors.New(fmt.Sprintf("assert request[%+v] to "+
+			"IntentRequest failed, type: %T", re.Request, re.Request))
+	}
+	if h := router.Lookup(req); h != nil {
+		return h(re, req)
+	}
+	log.Printf("INFO] Request[%s] intent %s has no handler, falling back",
+		req.GetRequestId(), req.IntentName())
+	if router.fallback == nil {
+		return nil, nil, errors.New(fmt.Sprintf("intent %s has no handler",
+			req.IntentName()))
+	}
+	return router.fallback(re, req)
+}
+
+func (router *IntentRouter) OnSessionEnded(re *RequestEnvelope) error {
+	if router.sessionEnded == nil {
+		return nil
+	}
+	return router.sessionEnded(re)
+}
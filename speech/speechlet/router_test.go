@@ -0,0 +1,96 @@
+package speechlet
+
+import (
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func routeIntent(t *testing.T, router *IntentRouter, intent *slu.Intent,
+	state slu.DialogState) *Response {
+	req := NewIntentRequest("req-1", "", intent).WithDialogState(state)
+	resp, _, err := router.OnIntent(&RequestEnvelope{Request: req})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func tellHandler(speech string) IntentHandlerFunc {
+	return func(re *RequestEnvelope, req *IntentRequest) (*Response, *Context, error) {
+		return NewTellResponse(speech), nil, nil
+	}
+}
+
+func speechOf(resp *Response) string {
+	if resp.GetFirstResult() == nil {
+		return ""
+	}
+	return resp.GetFirstResult().GetOutputSpeech().Items[0].GetSource()
+}
+
+func TestIntentRouter(t *testing.T) {
+	router := NewIntentRouter().
+		Handle("PlayMusic", tellHandler("music")).
+		Handle("PlayMusic/ByArtist", tellHandler("artist")).
+		OnStarted("PlanMyTrip", tellHandler("started")).
+		OnCompleted("PlanMyTrip", tellHandler("completed")).
+		OnCompleted("Book/Hotel", tellHandler("hotel booked"))
+	for _, c := range []struct {
+		name, sub string
+		state     slu.DialogState
+		want      string
+	}{
+		{"PlayMusic", "", slu.STARTED, "music"},
+		{"PlayMusic", "ByArtist", slu.STARTED, "artist"},
+		{"PlayMusic", "BySong", slu.COMPLETED, "music"},
+		{"PlanMyTrip", "", slu.STARTED, "started"},
+		{"PlanMyTrip", "", slu.COMPLETED, "completed"},
+		{"Book", "Hotel", slu.COMPLETED, "hotel booked"},
+		{HelpIntent, "", slu.STARTED, RouterHelpSpeech},
+		{StopIntent, "", slu.STARTED, RouterGoodbyeSpeech},
+		{CancelIntent, "", slu.STARTED, RouterGoodbyeSpeech},
+		{"Unknown", "", slu.STARTED, "暂不支持Unknown，请换个说法试试"},
+		{"Book", "Flight", slu.COMPLETED, "暂不支持Book，请换个说法试试"},
+	} {
+		resp := routeIntent(t, router, slu.NewIntent(c.name).WithSubName(c.sub), c.state)
+		if got := speechOf(resp); got != c.want {
+			t.Fatalf("%s/%s %s: want %q, got %q", c.name, c.sub, c.state, c.want, got)
+		}
+	}
+	// the other states of an intent with state handlers go to the dialog model
+	resp := routeIntent(t, router, slu.NewIntent("PlanMyTrip"), slu.IN_PROGRESS)
+	if !resp.HasDirectives() || speechOf(resp) != "" {
+		t.Fatalf("want delegate response, got %+v", resp)
+	}
+}
+
+func TestIntentRouterOverrides(t *testing.T) {
+	router := NewIntentRouter().
+		Handle(HelpIntent, tellHandler("custom help")).
+		WithFallback(tellHandler("fallback"))
+	if got := speechOf(routeIntent(t, router, slu.NewIntent(HelpIntent), slu.STARTED)); got != "custom help" {
+		t.Fatalf("want custom help, got %q", got)
+	}
+	if got := speechOf(routeIntent(t, router, slu.NewIntent("Unknown"), slu.STARTED)); got != "fallback" {
+		t.Fatalf("want fallback, got %q", got)
+	}
+	router.WithoutDefaults()
+	if got := speechOf(routeIntent(t, router, slu.NewIntent(StopIntent), slu.STARTED)); got != "fallback" {
+		t.Fatalf("want stop to fall back without defaults, got %q", got)
+	}
+	if router.Lookup(NewIntentRequest("req-1", "", slu.NewIntent(CancelIntent))) != nil {
+		t.Fatal("want no handler of cancel without defaults")
+	}
+}
+
+func TestIntentRouterNotIntentRequest(t *testing.T) {
+	router := NewIntentRouter()
+	if _, _, err := router.OnIntent(&RequestEnvelope{Request: NewLaunchRequest("req-1", "")}); err == nil {
+		t.Fatal("want error on a launch request")
+	}
+	resp, err := router.OnLaunch(nil)
+	if err != nil || speechOf(resp) != RouterWelcomeSpeech {
+		t.Fatalf("want welcome, got %+v, %v", resp, err)
+	}
+}
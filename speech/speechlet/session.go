@@ -18,6 +18,8 @@ import (
 
 const (
 	SSK_UPDATED_INTENT string = "updatedIntent"
+	SSK_DIALOG_STATE   string = "dialogState"
+	SSK_CARRY_OVER     string = "carryOver"
 )
 
 func init() {
@@ -145,6 +147,24 @@ func (ss *Session) ClearAllIntents() {
 	ss.Attributes[SSK_UPDATED_INTENT] = nil
 }
 
+// GetDialogStateMachine returns the dialog state machine of the session,
+// creating an empty one on first use.
+func (ss *Session) GetDialogStateMachine() *DialogStateMachine {
+	if ss.Attributes == nil {
+		ss.Attributes = make(map[string]interface{})
+	}
+	if v, ok := ss.Attributes[SSK_DIALOG_STATE].(*DialogStateMachine); ok && v != nil {
+		return v
+	}
+	dsm := NewDialogStateMachine()
+	ss.Attributes[SSK_DIALOG_STATE] = dsm
+	return dsm
+}
+
+func (ss *Session) ClearDialogState() {
+	delete(ss.Attributes, SSK_DIALOG_STATE)
+}
+
 func FetchSessionFromHistory(userId, appId, deviceId, skillId string) (*Session, error) {
 	ssStore := GetRediSession()
 	ss, err := ssStore.Fetch(userId, appId, deviceId, skillId)
@@ -0,0 +1,77 @@
+package speechlet
+
+import (
+	"sync"
+)
+
+// SessionStore keeps the sessions between the requests of a conversation.
+// RediSession is the one used by the RequestHandler unless another is set.
+type SessionStore interface {
+	Fetch(userId, appId, deviceId, skillId string) (*Session, error)
+	Save(ss *Session) error
+	Drop(userId, appId, deviceId, skillId string) error
+}
+
+var _ SessionStore = (*RediSession)(nil)
+
+// MemorySessionStore keeps the sessions in memory, serialized like in redis
+// so that the attribute types not registered with gob fail the same way. It
+// is meant for tests and single instance servers.
+type MemorySessionStore struct {
+	mu         sync.Mutex
+	sessions   map[string][]byte
+	serializer SessionSerializer
+}
+
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions:   make(map[string][]byte),
+		serializer: GobSerializer{},
+	}
+}
+
+func (s *MemorySessionStore) Fetch(userId, appId, deviceId, skillId string) (*Session, error) {
+	session := NewSession(userId, appId, deviceId, skillId)
+	s.mu.Lock()
+	data, ok := s.sessions[session.ID]
+	s.mu.Unlock()
+	if !ok {
+		return session, nil
+	}
+	session.New = false
+	return session, s.serializer.Deserialize(data, session)
+}
+
+func (s *MemorySessionStore) Save(ss *Session) error {
+	data, err := s.serializer.Serialize(ss)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[ss.ID] = data
+	return nil
+}
+
+func (s *MemorySessionStore) Drop(userId, appId, deviceId, skillId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, GenSessionId(userId, appId, deviceId, skillId))
+	return nil
+}
+
+// fetchSession fetches the session of the request from the SessionStore of
+// the handler, or from redis.
+func (rh *RequestHandler) fetchSession(userId, appId, deviceId, skillId string) (*Session, error) {
+	if rh.SessionStore == nil {
+		return FetchSessionFromHistory(userId, appId, deviceId, skillId)
+	}
+	return rh.SessionStore.Fetch(userId, appId, deviceId, skillId)
+}
+
+func (rh *RequestHandler) saveSession(ss *Session) error {
+	if rh.SessionStore == nil {
+		return PushSessionToCache(ss)
+	}
+	return rh.SessionStore.Save(ss)
+}
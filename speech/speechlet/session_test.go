@@ -67,3 +67,23 @@ func TestSessionOperate(t *testing.T) {
 	}
 	t.Logf("session got: %+v", ssGot)
 }
+
+func TestMemorySessionStore(t *testing.T) {
+	store := NewMemorySessionStore()
+	ss, err := store.Fetch(userId, appId, deviceId, skillId)
+	if err != nil || !ss.New {
+		t.Fatalf("want new session, got: %+v, %v", ss, err)
+	}
+	ss.GetDialogStateMachine().Begin("PlanMyTrip")
+	if err := store.Save(ss); err != nil {
+		t.Fatal(err)
+	}
+	ss, err = store.Fetch(userId, appId, deviceId, skillId)
+	if err != nil || ss.New || ss.GetDialogStateMachine().IntentName != "PlanMyTrip" {
+		t.Fatalf("want saved session, got: %+v, %v", ss, err)
+	}
+	store.Drop(userId, appId, deviceId, skillId)
+	if ss, _ = store.Fetch(userId, appId, deviceId, skillId); !ss.New {
+		t.Fatal("want session dropped")
+	}
+}
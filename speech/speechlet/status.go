@@ -13,6 +13,8 @@ const (
 	// The rosai skill has not received a valid response within the maximum allowed
 	// number of re-prompts.
 	EXCEEDED_MAX_REPROMPTS Reason = "EXCEEDED_MAX_REPROMPTS"
+	// The user did not answer before the timeout of the last response
+	TIMED_OUT Reason = "TIMED_OUT"
 )
 
 func NewGoodStatus() *Status {
@@ -26,6 +28,21 @@ func NewInternalErrStatus(detail string) *Status {
 	}
 }
 
+// ApologyErrorType is the error type of the status of the apology said in
+// place of the error by the FallbackPolicy.
+const ApologyErrorType = "apology"
+
+// NewApologyStatus is the status of the results apologizing for the error:
+// ApiSuccess, the device says nothing on a status 500, with the error in its
+// type and details.
+func NewApologyStatus(detail string) *Status {
+	return &Status{
+		Code:         ApiSuccess,
+		ErrorType:    ApologyErrorType,
+		ErrorDetails: detail,
+	}
+}
+
 func NewMismatchStatus(detail string) *Status {
 	return &Status{
 		Code:         ApiServiceMismatched,
@@ -44,6 +61,9 @@ type ApiStatusCode int
 var (
 	ErrServiceMismatched = errors.New("service_mismatched")
 	ErrServiceInternal   = errors.New("service_internal_error")
+	// ErrExceededMaxReprompts is returned when a slot has been elicited more
+	// times than RequestHandler.MaxReprompts allows.
+	ErrExceededMaxReprompts = errors.New("exceeded_max_reprompts")
 )
 
 const (
@@ -0,0 +1,84 @@
+package speechlet
+
+import (
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+// Suggestion is a follow-up shown as a chip on the screen devices. Tapping
+// it sends the utterance, or the text if it has none. When it carries an
+// intent, the IntentRequest of the tap carries the suggestion and its intent
+// is used as is, without going through NLU.
+type Suggestion struct {
+	Text      string      `json:"text"`
+	Utterance string      `json:"utterance,omitempty"`
+	Intent    *slu.Intent `json:"intent,omitempty"`
+}
+
+func NewSuggestion(text string) *Suggestion {
+	return &Suggestion{Text: text}
+}
+
+func (s *Suggestion) WithUtterance(utterance string) *Suggestion {
+	s.Utterance = utterance
+	return s
+}
+
+func (s *Suggestion) WithIntent(intent *slu.Intent) *Suggestion {
+	s.Intent = intent
+	return s
+}
+
+// GetUtterance returns what the user is taken to have said.
+func (s *Suggestion) GetUtterance() string {
+	if s == nil {
+		return ""
+	}
+	if s.Utterance != "" {
+		return s.Utterance
+	}
+	return s.Text
+}
+
+func (r *Result) WithSuggestions(ss ...*Suggestion) *Result {
+	r.Suggestions = append(r.Suggestions, ss...)
+	return r
+}
+
+// WithTextSuggestions adds suggestions which only carry a text.
+func (r *Result) WithTextSuggestions(texts ...string) *Result {
+	for _, v := range texts {
+		r.Suggestions = append(r.Suggestions, NewSuggestion(v))
+	}
+	return r
+}
+
+func (r *Result) GetSuggestions() []*Suggestion {
+	if r == nil {
+		return nil
+	}
+	return r.Suggestions
+}
+
+// NewIntentRequestFromSuggestion makes the request sent when the suggestion
+// is tapped.
+func NewIntentRequestFromSuggestion(reqId, ts string, s *Suggestion) *IntentRequest {
+	ir := NewIntentRequest(reqId, ts, nil)
+	ir.Suggestion = s
+	ir.applySuggestion()
+	return ir
+}
+
+// FromSuggestion reports whether the request comes from a tapped suggestion
+// carrying an intent.
+func (intReq *IntentRequest) FromSuggestion() bool {
+	return intReq != nil && intReq.Suggestion != nil && intReq.Suggestion.Intent != nil
+}
+
+// applySuggestion replaces the intent of the request with the one of the
+// tapped suggestion.
+func (intReq *IntentRequest) applySuggestion() {
+	if !intReq.FromSuggestion() {
+		return
+	}
+	intReq.Intent = intReq.Suggestion.Intent.Clone()
+}
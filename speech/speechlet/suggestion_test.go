@@ -0,0 +1,52 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+)
+
+func TestResultSuggestions(t *testing.T) {
+	intent := slu.NewIntent(IntentPlanMyTrip).
+		WithSlot(slu.NewSlot(SlotToCity).WithStringValue("上海"))
+	result := NewResult().WithTextSuggestions("帮助").
+		WithSuggestions(NewSuggestion("去上海").WithUtterance("我想去上海").WithIntent(intent))
+	bytes, _ := json.Marshal(NewResponseEnvelope().WithResults(result))
+	var raw ResponseEnvelopeRaw
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		t.Fatal(err)
+	}
+	results := raw.GetResults()
+	ss := results[0].GetSuggestions()
+	if len(ss) != 2 || ss[0].GetUtterance() != "帮助" || ss[1].GetUtterance() != "我想去上海" {
+		t.Fatalf("want 2 suggestions, got: %s", bytes)
+	}
+	if ss[1].Intent.GetSlot(SlotToCity).GetStringValue() != "上海" {
+		t.Fatalf("want pre-filled intent, got: %s", bytes)
+	}
+}
+
+func TestIntentRequestFromSuggestion(t *testing.T) {
+	intent := slu.NewIntent(IntentPlanMyTrip).
+		WithSlot(slu.NewSlot(SlotToCity).WithStringValue("上海"))
+	s := NewSuggestion("去上海").WithIntent(intent)
+	req := NewIntentRequestFromSuggestion("req-1", "", s)
+	if !req.FromSuggestion() || req.IntentName() != IntentPlanMyTrip {
+		t.Fatalf("want %s from suggestion, got: %+v", IntentPlanMyTrip, req.Intent)
+	}
+	req.Intent.SetSlot(slu.NewSlot(SlotToCity).WithStringValue("北京"))
+	if intent.GetSlot(SlotToCity).GetStringValue() != "上海" {
+		t.Fatal("want intent of the suggestion left untouched")
+	}
+	// the intent of NLU is replaced by the one of the suggestion
+	bytes, _ := json.Marshal(NewIntentRequest("req-2", "", slu.NewIntent("Other")))
+	var ir IntentRequest
+	ir.CoreRequest = CoreRequest{new(speechletRequest)}
+	json.Unmarshal(bytes, &ir)
+	ir.Suggestion = s
+	ir.applySuggestion()
+	if ir.IntentName() != IntentPlanMyTrip {
+		t.Fatalf("want %s, got: %s", IntentPlanMyTrip, ir.IntentName())
+	}
+}
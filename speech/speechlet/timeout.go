@@ -0,0 +1,79 @@
+package speechlet
+
+import (
+	"time"
+)
+
+const TimeoutRequestType RequestType = "TimeoutRequest"
+
+// TimeoutAction is what happens when the user stays silent after a response
+// for the time of its Timeout: TimeoutReprompt asks the last prompt again,
+// TimeoutEndSession ends the session and TimeoutEvent sends a custom event
+// to the skill.
+type TimeoutAction string
+
+const (
+	TimeoutReprompt   TimeoutAction = "Reprompt"
+	TimeoutEndSession TimeoutAction = "EndSession"
+	TimeoutEvent      TimeoutAction = "Event"
+)
+
+func NewTimeout(d time.Duration, action TimeoutAction) *Timeout {
+	return &Timeout{TimeInMillseconds: int(d / time.Millisecond), Action: action}
+}
+
+func NewRepromptTimeout(d time.Duration) *Timeout {
+	return NewTimeout(d, TimeoutReprompt)
+}
+
+func NewEndSessionTimeout(d time.Duration) *Timeout {
+	return NewTimeout(d, TimeoutEndSession)
+}
+
+func NewEventTimeout(d time.Duration, event string) *Timeout {
+	t := NewTimeout(d, TimeoutEvent)
+	t.Event = event
+	return t
+}
+
+func (r *Result) WithTimeout(t *Timeout) *Result {
+	r.Timeout = t
+	return r
+}
+
+func (r *Result) GetTimeout() *Timeout {
+	if r == nil {
+		return nil
+	}
+	return r.Timeout
+}
+
+// TimeoutRequest is sent by the device when the timeout of a response fires.
+type TimeoutRequest struct {
+	CoreRequest
+	Action   TimeoutAction `json:"action"`
+	Event    string        `json:"event,omitempty"`
+	TimeInMs int           `json:"timeInMs,omitempty"`
+}
+
+func NewTimeoutRequest(reqId, ts string, action TimeoutAction) *TimeoutRequest {
+	tr := new(TimeoutRequest)
+	tr.speechletRequest = new(speechletRequest)
+	tr.setBaseInfo(TimeoutRequestType, reqId, ts)
+	tr.Action = action
+	return tr
+}
+
+func (tr *TimeoutRequest) WithEvent(event string) *TimeoutRequest {
+	tr.Event = event
+	return tr
+}
+
+// TimeoutSpeechlet is implemented by the Speechlets which handle timeouts
+// themselves. When OnTimeout returns a nil response, the RequestHandler
+// falls back to the default behavior of the action: the last prompt of the
+// dialog is asked again for TimeoutReprompt, counting as a reprompt, and the
+// session is ended with TIMED_OUT for TimeoutEndSession.
+type TimeoutSpeechlet interface {
+	OnTimeout(requestEnvelope *RequestEnvelope) (*Response, error)
+}
@@ -0,0 +1,97 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/slu"
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/ui"
+)
+
+type quizSpeechlet struct {
+	Speechlet
+}
+
+func (qs *quizSpeechlet) OnSessionEnded(reqEn *RequestEnvelope) error {
+	return nil
+}
+
+func (qs *quizSpeechlet) OnTimeout(reqEn *RequestEnvelope) (*Response, error) {
+	req := reqEn.Request.(*TimeoutRequest)
+	if req.Action != TimeoutEvent {
+		return nil, nil
+	}
+	return NewTellResponse("时间到，答案是" + req.Event), nil
+}
+
+func TestTimeoutResult(t *testing.T) {
+	result := NewResult().WithOutputPlainTextSpeech("10秒内回答").
+		WithDisplayDirective(NewDisplayDirective().
+			WithCard(ui.NewTimerCard("倒计时", 10*time.Second))).
+		WithTimeout(NewEventTimeout(10*time.Second, "QuizTimeUp"))
+	bytes, _ := json.Marshal(NewResponseEnvelope().WithResults(result))
+	var raw ResponseEnvelopeRaw
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		t.Fatal(err)
+	}
+	got := raw.GetResults()[0]
+	if to := got.GetTimeout(); to == nil || to.Action != TimeoutEvent ||
+		to.Event != "QuizTimeUp" || to.TimeInMillseconds != 10000 {
+		t.Fatalf("want event timeout, got: %s", bytes)
+	}
+	card, ok := got.GetDisplayDirective().GetCard().(*ui.TimerCard)
+	if !ok || card.TimeInMs != 10000 {
+		t.Fatalf("want timer card, got: %s", bytes)
+	}
+}
+
+func TestHandleTimeoutRequest(t *testing.T) {
+	reqEn, err := ParseRequestEnvelope([]byte(`{"context":{},"request":{` +
+		`"type":"TimeoutRequest","requestId":"req","action":"Event","event":"42"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &RequestHandler{Speechlet: &quizSpeechlet{}}
+	resp, _, err := handler.handleTimeoutRequest(reqEn, NewSession("u", "a", "d", "s"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := resp.GetFirstResult().GetFirstOutputPlainTextSpeech(); text != "时间到，答案是42" {
+		t.Fatalf("want answer 42, got: %s", text)
+	}
+	// nothing to reprompt outside of a dialog, the session is ended
+	reqEn.Request = NewTimeoutRequest("req", "ts", TimeoutReprompt)
+	handler.SessionStore = NewMemorySessionStore()
+	resp, _, err = handler.handleTimeoutRequest(reqEn, NewSession("u", "a", "d", "s"), nil)
+	if err != nil || !resp.ShouldEnded() || len(resp.Results) != 0 {
+		t.Fatalf("want session ended, got: %+v, %v", resp, err)
+	}
+}
+
+func TestHandleTimeoutReprompt(t *testing.T) {
+	handler := newDialogHandler(t, confirmDialog)
+	handler.MaxReprompts = 1
+	timeout := func() *ResponseEnvelopeRaw {
+		return handlerCall(t, handler, NewTimeoutRequest("req", "ts", TimeoutReprompt), nil)
+	}
+	// the reprompt of date is resolved with the slots of the dialog
+	intent := tripIntent("toCity", "Paris")
+	intent.GetSlot("toCity").WithStatus(slu.CONFIRMED)
+	intentCall(t, handler, intent)
+	if got := envelopeSpeech(timeout()); got != "Which day to Paris?" {
+		t.Fatalf("want date reprompted, got %q", got)
+	}
+	// the confirmation of toCity is asked again until max reprompts
+	intentCall(t, handler, tripIntent("toCity", "Rome"))
+	for i, want := range []string{"Go to Rome?", ""} {
+		respEn := timeout()
+		if got := envelopeSpeech(respEn); got != want {
+			t.Fatalf("timeout %d: want %q, got %q", i, want, got)
+		}
+	}
+	session, _ := handler.SessionStore.Fetch("u", "a", "d", "s")
+	if session.GetDialogStateMachine().Active() {
+		t.Fatal("want dialog dropped after exceeded max reprompts")
+	}
+}
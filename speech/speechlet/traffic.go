@@ -0,0 +1,241 @@
+package speechlet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// TrafficRecord is a request handled by the RequestHandler, with its
+// response and the session before and after it, to be replayed later.
+type TrafficRecord struct {
+	Time          string          `json:"time"`
+	RequestId     string          `json:"requestId,omitempty"`
+	Request       json.RawMessage `json:"request"`
+	Response      json.RawMessage `json:"response,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	SessionBefore json.RawMessage `json:"sessionBefore,omitempty"`
+	SessionAfter  json.RawMessage `json:"sessionAfter,omitempty"`
+}
+
+// TrafficRecorder keeps the records of the requests handled, see
+// FileRecorder.
+type TrafficRecorder interface {
+	Record(rec *TrafficRecord) error
+}
+
+// recordCall handles the request as HandleCall does, and records it.
+func (rh *RequestHandler) recordCall(reqBytes []byte) ([]byte, error) {
+	if !json.Valid(reqBytes) {
+		return rh.handleCall(reqBytes)
+	}
+	rec := &TrafficRecord{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Request: json.RawMessage(reqBytes),
+	}
+	reqEn, err := ParseRequestEnvelope(reqBytes)
+	if err == nil && reqEn.Context != nil {
+		rec.RequestId = reqEn.Request.GetRequestId()
+		rec.SessionBefore = rh.sessionSnapshot(reqEn.Context)
+	}
+	respBytes, err := rh.handleCall(reqBytes)
+	if err != nil {
+		rec.Error = err.Error()
+	} else {
+		rec.Response = json.RawMessage(respBytes)
+	}
+	if reqEn != nil && reqEn.Context != nil {
+		rec.SessionAfter = rh.sessionSnapshot(reqEn.Context)
+	}
+	if err := rh.Recorder.Record(rec); err != nil {
+		log.Printf("Warning] record request[%s] error: %s", rec.RequestId, err)
+	}
+	return respBytes, err
+}
+
+func (rh *RequestHandler) sessionSnapshot(ctx *Context) json.RawMessage {
+	ss, err := rh.fetchSession(ctx.GetUserId(), ctx.GetAppId(), ctx.GetDeviceId(),
+		ctx.GetSkillId())
+	if err != nil {
+		log.Printf("Warning] snapshot session error: %s", err)
+		return nil
+	}
+	raw, err := json.Marshal(ss)
+	if err != nil {
+		log.Printf("Warning] snapshot session[%s] error: %s", ss.ID, err)
+		return nil
+	}
+	return raw
+}
+
+const (
+	DefaultMaxRecordBytes = 64 << 20
+	DefaultMaxRecordFiles = 5
+	RedactedValue         = "[REDACTED]"
+)
+
+// DefaultRedactedFields are the fields whose values are not recorded.
+var DefaultRedactedFields = []string{"accessToken"}
+
+// FileRecorder writes the records as JSON lines to a file. When the file
+// grows over MaxBytes it is renamed to path.1, the former path.1 to path.2
+// and so on, keeping MaxFiles old files.
+type FileRecorder struct {
+	Path     string
+	MaxBytes int64
+	MaxFiles int
+	// Redact are the fields, at any depth, whose values are replaced with
+	// RedactedValue. The requests replayed carry that value in place of the
+	// access tokens, see replay.Replayer.WithAccessToken.
+	Redact []string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewFileRecorder(path string) *FileRecorder {
+	return &FileRecorder{
+		Path:     path,
+		MaxBytes: DefaultMaxRecordBytes,
+		MaxFiles: DefaultMaxRecordFiles,
+		Redact:   DefaultRedactedFields,
+	}
+}
+
+func (fr *FileRecorder) WithMaxBytes(n int64) *FileRecorder {
+	fr.MaxBytes = n
+	return fr
+}
+
+func (fr *FileRecorder) WithMaxFiles(n int) *FileRecorder {
+	fr.MaxFiles = n
+	return fr
+}
+
+func (fr *FileRecorder) WithRedactedFields(fields ...string) *FileRecorder {
+	fr.Redact = fields
+	return fr
+}
+
+func (fr *FileRecorder) Record(rec *TrafficRecord) error {
+	redacted := *rec
+	for _, v := range []*json.RawMessage{&redacted.Request, &redacted.Response,
+		&redacted.SessionBefore, &redacted.SessionAfter} {
+		if len(*v) == 0 {
+			continue
+		}
+		raw, err := RedactJSON(*v, fr.Redact...)
+		if err != nil {
+			return err
+		}
+		*v = raw
+	}
+	line, err := json.Marshal(&redacted)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	if fr.file != nil && fr.MaxBytes > 0 && fr.size > 0 &&
+		fr.size+int64(len(line)) > fr.MaxBytes {
+		if err := fr.rotate(); err != nil {
+			return err
+		}
+	}
+	if fr.file == nil {
+		if err := fr.open(); err != nil {
+			return err
+		}
+	}
+	n, err := fr.file.Write(line)
+	fr.size += int64(n)
+	return err
+}
+
+func (fr *FileRecorder) open() error {
+	f, err := os.OpenFile(fr.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	fr.file, fr.size = f, info.Size()
+	return nil
+}
+
+func (fr *FileRecorder) rotate() error {
+	if err := fr.file.Close(); err != nil {
+		return err
+	}
+	fr.file = nil
+	if fr.MaxFiles <= 0 {
+		return os.Remove(fr.Path)
+	}
+	for i := fr.MaxFiles; i > 1; i-- {
+		old := fmt.Sprintf("%s.%d", fr.Path, i-1)
+		if _, err := os.Stat(old); err == nil {
+			if err := os.Rename(old, fmt.Sprintf("%s.%d", fr.Path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(fr.Path, fr.Path+".1")
+}
+
+func (fr *FileRecorder) Close() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	if fr.file == nil {
+		return nil
+	}
+	err := fr.file.Close()
+	fr.file = nil
+	return err
+}
+
+// RedactJSON replaces the values of the fields, at any depth, with
+// RedactedValue.
+func RedactJSON(raw json.RawMessage, fields ...string) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return raw, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.New(fmt.Sprintf("redact: %s", err))
+	}
+	redact := make(map[string]bool, len(fields))
+	for _, v := range fields {
+		redact[v] = true
+	}
+	redactValue(doc, redact)
+	return json.Marshal(doc)
+}
+
+func redactValue(v interface{}, redact map[string]bool) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			if redact[k] {
+				vv[k] = RedactedValue
+				continue
+			}
+			redactValue(e, redact)
+		}
+	case []interface{}:
+		for _, e := range vv {
+			redactValue(e, redact)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package speechlet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"roobo.com/rosai-skills-kit-sdk-for-go/speech/dialog/model"
+)
+
+type greetSpeechlet struct {
+	Speechlet
+}
+
+func (gs *greetSpeechlet) OnSessionStarted(reqEn *RequestEnvelope) error {
+	return nil
+}
+
+func (gs *greetSpeechlet) OnLaunch(reqEn *RequestEnvelope) (*Response, error) {
+	return NewAskResponse("你好"), nil
+}
+
+func TestFileRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traffic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traffic.jsonl")
+	recorder := NewFileRecorder(path).WithMaxBytes(1).WithMaxFiles(1)
+	handler := &RequestHandler{
+		Speechlet:    &greetSpeechlet{},
+		DialogModel:  &model.DialogModel{},
+		SessionStore: NewMemorySessionStore(),
+		Recorder:     recorder,
+	}
+	for _, v := range []string{"req1", "req2", "req3"} {
+		reqEn := NewRequestEnvelope().WithContext(NewContext().WithSystem(NewCtxSystem().
+			WithUser(NewUser("u", "a").WithAccessToken("secret")).
+			WithDevice(NewDevice("d")).WithSkill(NewSkill("s")))).
+			WithRequest(NewLaunchRequest(v, "2018-04-06T15:30:02+08:00"))
+		reqBytes, _ := json.Marshal(reqEn)
+		if _, err := handler.HandleCall(reqBytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder.Close()
+	// each record rotates the file, only one old file is kept
+	for name, want := range map[string]string{path: "req3", path + ".1": "req2"} {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret") {
+			t.Fatalf("want access token redacted, got: %s", data)
+		}
+		rec := new(TrafficRecord)
+		if err := json.Unmarshal(data, rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.RequestId != want || len(rec.Response) == 0 ||
+			len(rec.SessionBefore) == 0 || len(rec.SessionAfter) == 0 {
+			t.Fatalf("want record of %s, got: %s", want, data)
+		}
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Fatalf("want %s.2 removed, got: %v", path, err)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	raw, err := RedactJSON(json.RawMessage(`{"user":{"accessToken":"t","id":1},`+
+		`"list":[{"accessToken":"t"}]}`), "accessToken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"list":[{"accessToken":"[REDACTED]"}],"user":{"accessToken":"[REDACTED]","id":1}}`
+	if string(raw) != want {
+		t.Fatalf("want %s, got: %s", want, raw)
+	}
+}
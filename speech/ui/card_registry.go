@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	cardTypesMu sync.RWMutex
+	cardTypes   = map[CardType]func() CardInterface{
+		TextCardType:     func() CardInterface { return &TextCard{} },
+		StandardCardType: func() CardInterface { return &StandardCard{} },
+		ImagesCardType:   func() CardInterface { return &ImagesCard{} },
+		ListCardType:     func() CardInterface { return &ListCard{} },
+		TimerCardType:    func() CardInterface { return &TimerCard{} },
+	}
+)
+
+// RegisterCardType makes UnmarshalCard decode the cards of type typ into the
+// value returned by newCard, which must be a pointer.
+func RegisterCardType(typ CardType, newCard func() CardInterface) {
+	cardTypesMu.Lock()
+	defer cardTypesMu.Unlock()
+	cardTypes[typ] = newCard
+}
+
+// UnmarshalCard decodes a card into the type registered for its "type".
+func UnmarshalCard(raw []byte) (CardInterface, error) {
+	var typePre struct {
+		Type CardType `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &typePre); err != nil {
+		return nil, err
+	}
+	cardTypesMu.RLock()
+	newCard, ok := cardTypes[typePre.Type]
+	cardTypesMu.RUnlock()
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unrecognized card type: %s", typePre.Type))
+	}
+	card := newCard()
+	if err := json.Unmarshal(raw, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// JSONSchema is the subset of JSON Schema used to describe the data of the
+// H5 templates: type, enum, required, properties, additionalProperties,
+// items, minItems, maxItems, minLength, maxLength, minimum and maximum.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+}
+
+func ParseJSONSchema(raw []byte) (*JSONSchema, error) {
+	schema := new(JSONSchema)
+	if err := json.Unmarshal(raw, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Validate checks v, which may be any value encoding to JSON.
+func (s *JSONSchema) Validate(v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	return s.validate("$", doc)
+}
+
+func (s *JSONSchema) validate(path string, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+	if s.Type != "" && !matchJSONType(s.Type, v) {
+		return errors.New(fmt.Sprintf("%s: want %s, got %s", path, s.Type, jsonTypeOf(v)))
+	}
+	if len(s.Enum) > 0 && !inJSONEnum(s.Enum, v) {
+		return errors.New(fmt.Sprintf("%s: %v not in enum %v", path, v, s.Enum))
+	}
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for _, k := range s.Required {
+			if _, ok := vv[k]; !ok {
+				return errors.New(fmt.Sprintf("%s: missing required property %s", path, k))
+			}
+		}
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ps, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return errors.New(fmt.Sprintf("%s: property %s not allowed", path, k))
+				}
+				continue
+			}
+			if err := ps.validate(path+"."+k, vv[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(vv) < *s.MinItems {
+			return errors.New(fmt.Sprintf("%s: want at least %d items, got %d", path,
+				*s.MinItems, len(vv)))
+		}
+		if s.MaxItems != nil && len(vv) > *s.MaxItems {
+			return errors.New(fmt.Sprintf("%s: want at most %d items, got %d", path,
+				*s.MaxItems, len(vv)))
+		}
+		for i, e := range vv {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), e); err != nil {
+				return err
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(vv)
+		if s.MinLength != nil && n < *s.MinLength {
+			return errors.New(fmt.Sprintf("%s: want at least %d characters, got %d", path,
+				*s.MinLength, n))
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return errors.New(fmt.Sprintf("%s: want at most %d characters, got %d", path,
+				*s.MaxLength, n))
+		}
+	case float64:
+		if s.Minimum != nil && vv < *s.Minimum {
+			return errors.New(fmt.Sprintf("%s: %v less than minimum %v", path, vv, *s.Minimum))
+		}
+		if s.Maximum != nil && vv > *s.Maximum {
+			return errors.New(fmt.Sprintf("%s: %v greater than maximum %v", path, vv,
+				*s.Maximum))
+		}
+	}
+	return nil
+}
+
+func matchJSONType(typ string, v interface{}) bool {
+	if typ == "integer" {
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return jsonTypeOf(v) == typ
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inJSONEnum(enum []interface{}, v interface{}) bool {
+	raw, _ := json.Marshal(v)
+	for _, e := range enum {
+		if er, _ := json.Marshal(e); string(er) == string(raw) {
+			return true
+		}
+	}
+	return false
+}
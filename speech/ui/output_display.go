@@ -1,5 +1,10 @@
 package ui
 
+import (
+	"strings"
+	"time"
+)
+
 type CardType string
 
 const (
@@ -102,6 +107,27 @@ func (lc *ListCard) AppendCards(list ...*StandardCard) *ListCard {
 	return lc
 }
 
+// TimerCard shows a countdown of TimeInMs on the screen.
+type TimerCard struct {
+	Type     CardType `json:"type"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content,omitempty"`
+	TimeInMs int64    `json:"timeInMs"`
+}
+
+func NewTimerCard(title string, d time.Duration) *TimerCard {
+	return &TimerCard{Type: TimerCardType, Title: title, TimeInMs: int64(d / time.Millisecond)}
+}
+
+func (tc *TimerCard) GetType() CardType {
+	return tc.Type
+}
+
+func (tc *TimerCard) WithContent(content string) *TimerCard {
+	tc.Content = content
+	return tc
+}
+
 type BulletPositionType string
 
 const (
@@ -160,3 +186,28 @@ func (i *Image) WithBullet(bs *BulletScreen) *Image {
 	i.BulletScreen = bs
 	return i
 }
+
+// CardText returns the text shown by the card, for the devices without a
+// screen: the title and content, or the titles of a list.
+func CardText(card CardInterface) string {
+	var texts []string
+	switch c := card.(type) {
+	case *TextCard:
+		texts = []string{c.Title, c.Content}
+	case *StandardCard:
+		texts = []string{c.Title, c.Content}
+	case *TimerCard:
+		texts = []string{c.Title, c.Content}
+	case *ListCard:
+		for _, v := range c.List {
+			texts = append(texts, v.Title)
+		}
+	}
+	nonEmpty := texts[:0]
+	for _, v := range texts {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+	return strings.Join(nonEmpty, "，")
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 const (
@@ -117,3 +118,31 @@ func TestMakeCards(t *testing.T) {
 		t.Fatalf("list card want: %s, got: %s", listCardOutput, string(bytes))
 	}
 }
+
+func TestUnmarshalCard(t *testing.T) {
+	card, err := UnmarshalCard([]byte(standardCardOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, ok := card.(*StandardCard)
+	if !ok || sc.GetTitle() != "standard card" || sc.Image.BulletScreen == nil {
+		t.Fatalf("want standard card, got: %#v", card)
+	}
+	if _, err := UnmarshalCard([]byte(`{"type":"Unknown"}`)); err == nil {
+		t.Fatal("unknown card type should fail")
+	}
+}
+
+func TestTimerCard(t *testing.T) {
+	bytes, _ := json.Marshal(NewTimerCard("倒计时", 1500*time.Millisecond).WithContent("快回答"))
+	if string(bytes) != `{"type":"Timer","title":"倒计时","content":"快回答","timeInMs":1500}` {
+		t.Fatalf("got: %s", bytes)
+	}
+	card, err := UnmarshalCard(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc, ok := card.(*TimerCard); !ok || tc.TimeInMs != 1500 {
+		t.Fatalf("want timer card, got: %#v", card)
+	}
+}
@@ -1,5 +1,12 @@
 package ui
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
 type ScriptItemInterface interface {
 	GetType() string
 	GetSource() string
@@ -8,12 +15,19 @@ type ScriptItemInterface interface {
 type ScriptType string
 
 const (
-	H5Type ScriptType = "H5"
+	H5Type         ScriptType = "H5"
+	H5TemplateType ScriptType = "H5Template"
 )
 
 type ScriptItem struct {
 	Type   ScriptType `json:"type"`
 	Source string     `json:"source"`
+	// The fields of H5Template items: the template is rendered by the device
+	// with the data, or the fallback speech is said if it can not.
+	TemplateId     string          `json:"templateId,omitempty"`
+	Version        string          `json:"version,omitempty"`
+	Data           json.RawMessage `json:"data,omitempty"`
+	FallbackSpeech *SpeechItem     `json:"fallbackSpeech,omitempty"`
 }
 
 func (si *ScriptItem) GetType() ScriptType {
@@ -35,3 +49,82 @@ func NewScriptItem(typ ScriptType, s string) *ScriptItem {
 func NewH5ScriptItem(text string) *ScriptItem {
 	return &ScriptItem{Type: H5Type, Source: text}
 }
+
+// NewH5TemplateScriptItem makes an item rendering the H5 template with the
+// data, which is validated against the schema of the template if one is
+// registered.
+func NewH5TemplateScriptItem(templateId, version string, data interface{}) (*ScriptItem, error) {
+	if err := ValidateH5Data(templateId, data); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &ScriptItem{Type: H5TemplateType, TemplateId: templateId, Version: version,
+		Data: raw}, nil
+}
+
+func (si *ScriptItem) WithFallbackSpeech(text string) *ScriptItem {
+	si.FallbackSpeech = NewPlainTextSpeechItem(text)
+	return si
+}
+
+func (si *ScriptItem) WithFallbackSpeechItem(item *SpeechItem) *ScriptItem {
+	si.FallbackSpeech = item
+	return si
+}
+
+// Validate checks the data of an H5Template item against the schema of its
+// template.
+func (si *ScriptItem) Validate() error {
+	if si.Type != H5TemplateType {
+		return nil
+	}
+	if si.TemplateId == "" {
+		return errors.New("h5 template id is empty")
+	}
+	var data interface{}
+	if len(si.Data) > 0 {
+		if err := json.Unmarshal(si.Data, &data); err != nil {
+			return err
+		}
+	}
+	return ValidateH5Data(si.TemplateId, data)
+}
+
+var (
+	h5SchemasMu sync.RWMutex
+	h5Schemas   = make(map[string]*JSONSchema)
+)
+
+// RegisterH5Template registers the JSON schema of the data of the template.
+func RegisterH5Template(templateId string, schema []byte) error {
+	s, err := ParseJSONSchema(schema)
+	if err != nil {
+		return errors.New(fmt.Sprintf("h5 template %s schema: %s", templateId, err))
+	}
+	h5SchemasMu.Lock()
+	defer h5SchemasMu.Unlock()
+	h5Schemas[templateId] = s
+	return nil
+}
+
+func GetH5TemplateSchema(templateId string) *JSONSchema {
+	h5SchemasMu.RLock()
+	defer h5SchemasMu.RUnlock()
+	return h5Schemas[templateId]
+}
+
+// ValidateH5Data validates the data against the schema registered for the
+// template, the data of templates without schema is not checked.
+func ValidateH5Data(templateId string, data interface{}) error {
+	s := GetH5TemplateSchema(templateId)
+	if s == nil {
+		return nil
+	}
+	if err := s.Validate(data); err != nil {
+		return errors.New(fmt.Sprintf("h5 template %s data: %s", templateId, err))
+	}
+	return nil
+}
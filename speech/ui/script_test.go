@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const weatherSchema = `{
+  "type": "object",
+  "required": ["city", "days"],
+  "additionalProperties": false,
+  "properties": {
+    "city": {"type": "string", "minLength": 1},
+    "unit": {"type": "string", "enum": ["C", "F"]},
+    "days": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "type": "object",
+        "required": ["date", "high"],
+        "properties": {
+          "date": {"type": "string"},
+          "high": {"type": "integer", "minimum": -90, "maximum": 60}
+        }
+      }
+    }
+  }
+}`
+
+type weatherDay struct {
+	Date string `json:"date"`
+	High int    `json:"high"`
+}
+
+func TestH5TemplateScriptItem(t *testing.T) {
+	if err := RegisterH5Template("weather.days", []byte(weatherSchema)); err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"city": "北京",
+		"days": []*weatherDay{{"2018-06-21", 35}},
+	}
+	item, err := NewH5TemplateScriptItem("weather.days", "1.0", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item.WithFallbackSpeech("北京今天最高35度")
+	bytes, _ := json.Marshal(item)
+	want := `{"type":"H5Template","source":"","templateId":"weather.days","version":"1.0",` +
+		`"data":{"city":"北京","days":[{"date":"2018-06-21","high":35}]},` +
+		`"fallbackSpeech":{"type":"PlainText","source":"北京今天最高35度"}}`
+	if string(bytes) != want {
+		t.Fatalf("want: %s, got: %s", want, bytes)
+	}
+	var got ScriptItem
+	if err := json.Unmarshal(bytes, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateH5Data(t *testing.T) {
+	if err := RegisterH5Template("weather.days", []byte(weatherSchema)); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []string{
+		`{"days": [{"date": "2018-06-21", "high": 35}]}`,
+		`{"city": "", "days": [{"date": "2018-06-21", "high": 35}]}`,
+		`{"city": "北京", "days": []}`,
+		`{"city": "北京", "days": [{"date": "2018-06-21", "high": 35.5}]}`,
+		`{"city": "北京", "days": [{"date": "2018-06-21", "high": 99}]}`,
+		`{"city": "北京", "unit": "K", "days": [{"date": "2018-06-21", "high": 35}]}`,
+		`{"city": "北京", "html": "<b>", "days": [{"date": "2018-06-21", "high": 35}]}`,
+	}
+	for _, v := range invalid {
+		if err := ValidateH5Data("weather.days", json.RawMessage(v)); err == nil {
+			t.Fatalf("data %s should be invalid", v)
+		}
+	}
+	if _, err := NewH5TemplateScriptItem("weather.days", "1.0", 42); err == nil {
+		t.Fatal("number data should be invalid")
+	}
+	// templates without schema are not checked
+	if err := ValidateH5Data("unknown", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterH5Template("broken", []byte("{")); err == nil {
+		t.Fatal("broken schema should fail")
+	}
+}
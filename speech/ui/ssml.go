@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSMLTags lists the SSML elements supported by the devices, with their
+// allowed attributes.
+var SSMLTags = map[string][]string{
+	"speak":    nil,
+	"p":        nil,
+	"s":        nil,
+	"break":    {"time", "strength"},
+	"say-as":   {"interpret-as", "format"},
+	"prosody":  {"rate", "pitch", "volume"},
+	"emphasis": {"level"},
+	"audio":    {"src"},
+	"sub":      {"alias"},
+	"phoneme":  {"alphabet", "ph"},
+}
+
+// SSMLBuilder builds an SSML document, escaping the text it is given:
+//
+//	ui.SSML().Say("现在是").SayAs("time", "10:30", "hms24").
+//		Break(300 * time.Millisecond).Audio(url).String()
+type SSMLBuilder struct {
+	buf bytes.Buffer
+}
+
+func SSML() *SSMLBuilder {
+	return &SSMLBuilder{}
+}
+
+func (b *SSMLBuilder) Say(text string) *SSMLBuilder {
+	xml.EscapeText(&b.buf, []byte(text))
+	return b
+}
+
+// Break pauses for d, rounded to the millisecond.
+func (b *SSMLBuilder) Break(d time.Duration) *SSMLBuilder {
+	ms := strconv.FormatInt(int64(d/time.Millisecond), 10)
+	return b.element("break", "", "time", ms+"ms")
+}
+
+// SayAs tells how to read the text, e.g. as a "date", "time", "telephone"
+// or "digits". The format is optional.
+func (b *SSMLBuilder) SayAs(interpretAs, text string, format ...string) *SSMLBuilder {
+	attrs := []string{"interpret-as", interpretAs}
+	if len(format) > 0 {
+		attrs = append(attrs, "format", format[0])
+	}
+	return b.element("say-as", text, attrs...)
+}
+
+// Prosody reads the text with the rate, pitch and volume given, the empty
+// ones are left out.
+func (b *SSMLBuilder) Prosody(text, rate, pitch, volume string) *SSMLBuilder {
+	return b.element("prosody", text, "rate", rate, "pitch", pitch, "volume", volume)
+}
+
+func (b *SSMLBuilder) Emphasis(level, text string) *SSMLBuilder {
+	return b.element("emphasis", text, "level", level)
+}
+
+func (b *SSMLBuilder) Sub(alias, text string) *SSMLBuilder {
+	return b.element("sub", text, "alias", alias)
+}
+
+func (b *SSMLBuilder) Phoneme(alphabet, ph, text string) *SSMLBuilder {
+	return b.element("phoneme", text, "alphabet", alphabet, "ph", ph)
+}
+
+func (b *SSMLBuilder) Paragraph(text string) *SSMLBuilder {
+	return b.element("p", text)
+}
+
+func (b *SSMLBuilder) Sentence(text string) *SSMLBuilder {
+	return b.element("s", text)
+}
+
+func (b *SSMLBuilder) Audio(url string) *SSMLBuilder {
+	return b.element("audio", "", "src", url)
+}
+
+// String returns the document wrapped in a speak element.
+func (b *SSMLBuilder) String() string {
+	return "<speak>" + b.buf.String() + "</speak>"
+}
+
+func (b *SSMLBuilder) SpeechItem() *SpeechItem {
+	return NewSsmlSpeechItem(b.String())
+}
+
+// element writes a tag with the attributes given as name, value pairs,
+// leaving out the empty ones. It is self-closed when text is empty.
+func (b *SSMLBuilder) element(tag, text string, attrs ...string) *SSMLBuilder {
+	b.buf.WriteString("<" + tag)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i+1] == "" {
+			continue
+		}
+		b.buf.WriteString(" " + attrs[i] + `="`)
+		xml.EscapeText(&b.buf, []byte(attrs[i+1]))
+		b.buf.WriteString(`"`)
+	}
+	if text == "" {
+		b.buf.WriteString("/>")
+		return b
+	}
+	b.buf.WriteString(">")
+	xml.EscapeText(&b.buf, []byte(text))
+	b.buf.WriteString("</" + tag + ">")
+	return b
+}
+
+// EscapeSSML escapes the text to be put in an SSML document.
+func EscapeSSML(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// ValidateSSML checks that the document is well-formed, has a speak root and
+// only uses the elements and attributes of SSMLTags.
+func ValidateSSML(ssml string) error {
+	return walkSSML(ssml, nil)
+}
+
+// SSMLToPlainText returns the text read out by the document, as a fallback
+// for the devices without SSML support. Audio is dropped and sub elements are
+// replaced by their alias.
+func SSMLToPlainText(ssml string) (string, error) {
+	var (
+		text  strings.Builder
+		inSub bool
+	)
+	err := walkSSML(ssml, func(tok xml.Token) {
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "sub" {
+				inSub = true
+				text.WriteString(attrValue(t, "alias"))
+			}
+		case xml.CharData:
+			if !inSub {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "sub" {
+				inSub = false
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// walkSSML validates the document while handing its tokens to visit.
+func walkSSML(ssml string, visit func(tok xml.Token)) error {
+	dec := xml.NewDecoder(strings.NewReader(ssml))
+	depth, elements := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.New(fmt.Sprintf("ssml not well-formed: %s", err))
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if depth == 0 && (elements > 0 || name != "speak") {
+				return errors.New(fmt.Sprintf("ssml root must be a single speak, got: %s",
+					name))
+			}
+			if depth > 0 && name == "speak" {
+				return errors.New("ssml speak can not be nested")
+			}
+			allowed, ok := SSMLTags[name]
+			if !ok {
+				return errors.New(fmt.Sprintf("ssml tag %s not supported", name))
+			}
+			for _, a := range t.Attr {
+				if !containsString(allowed, a.Name.Local) {
+					return errors.New(fmt.Sprintf("ssml attribute %s of %s not supported",
+						a.Name.Local, name))
+				}
+			}
+			depth++
+			elements++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return errors.New("ssml text outside of speak")
+			}
+		}
+		if visit != nil {
+			visit(tok)
+		}
+	}
+	if elements == 0 {
+		return errors.New("ssml root must be a single speak, got none")
+	}
+	return nil
+}
+
+func attrValue(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
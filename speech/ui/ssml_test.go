@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSSMLBuilder(t *testing.T) {
+	ssml := SSML().Say("北京 & 上海").Break(300*time.Millisecond).
+		SayAs("date", "2018-06-21", "ymd").
+		Prosody("多云", "slow", "", "loud").
+		Emphasis("strong", "注意").
+		Audio("https://ai.roobo.com/a.mp3?x=1&y=2").String()
+	want := `<speak>北京 &amp; 上海<break time="300ms"/>` +
+		`<say-as interpret-as="date" format="ymd">2018-06-21</say-as>` +
+		`<prosody rate="slow" volume="loud">多云</prosody>` +
+		`<emphasis level="strong">注意</emphasis>` +
+		`<audio src="https://ai.roobo.com/a.mp3?x=1&amp;y=2"/></speak>`
+	if ssml != want {
+		t.Fatalf("want: %s, got: %s", want, ssml)
+	}
+	if err := ValidateSSML(ssml); err != nil {
+		t.Fatal(err)
+	}
+	if item := SSML().Say("hi").SpeechItem(); item.GetType() != SSMLType {
+		t.Fatalf("want SSML item, got: %s", item.GetType())
+	}
+}
+
+func TestValidateSSML(t *testing.T) {
+	invalid := []string{
+		"",
+		"plain text",
+		"<speak>unclosed",
+		"<p>not speak</p>",
+		"<speak>a</speak><speak>b</speak>",
+		"<speak><speak>nested</speak></speak>",
+		"<speak><voice name=\"x\">unsupported</voice></speak>",
+		"<speak><break duration=\"1s\"/></speak>",
+	}
+	for _, v := range invalid {
+		if err := ValidateSSML(v); err == nil {
+			t.Fatalf("ssml %q should be invalid", v)
+		}
+	}
+	if err := ValidateSSML(" <speak><p><s>ok</s></p></speak> "); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSSMLToPlainText(t *testing.T) {
+	ssml := SSML().Say("现在是").SayAs("time", "10:30").Break(time.Second).
+		Sub("世界卫生组织", "WHO").Audio("https://ai.roobo.com/a.mp3").Say(" a<b").String()
+	text, err := SSMLToPlainText(ssml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "现在是10:30世界卫生组织 a<b" {
+		t.Fatalf("got: %s", text)
+	}
+	if _, err := SSMLToPlainText("<speak>"); err == nil {
+		t.Fatal("malformed ssml should fail")
+	}
+}